@@ -154,6 +154,168 @@ process_mermaid() {
     return 0
 }
 
+# Function to process dot.yaml and generate dot.gv
+# Arguments:
+#   $1 - Directory path
+#   $2 - Depth level
+process_dot() {
+    local dir="${1%/}"
+    local depth=$2
+
+    [ ! -f "$dir/dot.yaml" ] && return 0
+
+    if ! execute_command "./YAMLtecture --validateDot --dotIn=$dir/dot.yaml" "$depth" "dot.yaml" "Valid" "no"; then
+        return 1
+    fi
+
+    if ! execute_command "./YAMLtecture --generateDot --configIn=$dir/config.yaml --dotIn=$dir/dot.yaml --out=$dir/dot.gv" "$depth" "dot.gv" "Generated" "no"; then
+        return 1
+    fi
+
+    return 0
+}
+
+# Function to process plantuml.yaml and generate plantuml.puml
+# Arguments:
+#   $1 - Directory path
+#   $2 - Depth level
+process_plantuml() {
+    local dir="${1%/}"
+    local depth=$2
+
+    [ ! -f "$dir/plantuml.yaml" ] && return 0
+
+    if ! execute_command "./YAMLtecture --validatePlantUML --plantumlIn=$dir/plantuml.yaml" "$depth" "plantuml.yaml" "Valid" "no"; then
+        return 1
+    fi
+
+    if ! execute_command "./YAMLtecture --generatePlantUML --configIn=$dir/config.yaml --plantumlIn=$dir/plantuml.yaml --out=$dir/plantuml.puml" "$depth" "plantuml.puml" "Generated" "no"; then
+        return 1
+    fi
+
+    return 0
+}
+
+# Function to process c4.yaml and generate c4.mmd
+# Arguments:
+#   $1 - Directory path
+#   $2 - Depth level
+process_c4() {
+    local dir="${1%/}"
+    local depth=$2
+
+    [ ! -f "$dir/c4.yaml" ] && return 0
+
+    if ! execute_command "./YAMLtecture --validateC4 --c4In=$dir/c4.yaml" "$depth" "c4.yaml" "Valid" "no"; then
+        return 1
+    fi
+
+    if ! execute_command "./YAMLtecture --generateC4 --configIn=$dir/config.yaml --c4In=$dir/c4.yaml --out=$dir/c4.mmd" "$depth" "c4.mmd" "Generated" "no"; then
+        return 1
+    fi
+
+    return 0
+}
+
+# Function to process structurizr.yaml and generate structurizr.dsl
+# Arguments:
+#   $1 - Directory path
+#   $2 - Depth level
+process_structurizr() {
+    local dir="${1%/}"
+    local depth=$2
+
+    [ ! -f "$dir/structurizr.yaml" ] && return 0
+
+    if ! execute_command "./YAMLtecture --validateStructurizr --structurizrIn=$dir/structurizr.yaml" "$depth" "structurizr.yaml" "Valid" "no"; then
+        return 1
+    fi
+
+    if ! execute_command "./YAMLtecture --generateStructurizr --configIn=$dir/config.yaml --structurizrIn=$dir/structurizr.yaml --out=$dir/structurizr.dsl" "$depth" "structurizr.dsl" "Generated" "no"; then
+        return 1
+    fi
+
+    return 0
+}
+
+# Function to process d2.yaml and generate d2.d2
+# Arguments:
+#   $1 - Directory path
+#   $2 - Depth level
+process_d2() {
+    local dir="${1%/}"
+    local depth=$2
+
+    [ ! -f "$dir/d2.yaml" ] && return 0
+
+    if ! execute_command "./YAMLtecture --validateD2 --d2In=$dir/d2.yaml" "$depth" "d2.yaml" "Valid" "no"; then
+        return 1
+    fi
+
+    if ! execute_command "./YAMLtecture --generateD2 --configIn=$dir/config.yaml --d2In=$dir/d2.yaml --out=$dir/d2.d2" "$depth" "d2.d2" "Generated" "no"; then
+        return 1
+    fi
+
+    return 0
+}
+
+# Function to process lint.yaml and generate lint.txt
+# Arguments:
+#   $1 - Directory path
+#   $2 - Depth level
+process_lint() {
+    local dir="${1%/}"
+    local depth=$2
+
+    [ ! -f "$dir/lint.yaml" ] && return 0
+
+    if ! execute_command "./YAMLtecture --validateLint --lintIn=$dir/lint.yaml" "$depth" "lint.yaml" "Valid" "no"; then
+        return 1
+    fi
+
+    # Violations with the error severity exit with 1 once the output is written
+    if ! execute_command "./YAMLtecture --lint --configIn=$dir/config.yaml --lintIn=$dir/lint.yaml --out=$dir/lint.txt || [ \$? -eq 1 ]" "$depth" "lint.txt" "Generated" "no"; then
+        return 1
+    fi
+
+    return 0
+}
+
+# Function to regenerate cycles.txt for the directories that include it
+# Arguments:
+#   $1 - Directory path
+#   $2 - Depth level
+process_cycles() {
+    local dir="${1%/}"
+    local depth=$2
+
+    [ ! -f "$dir/cycles.txt" ] && return 0
+
+    # Finding a cycle exits with 1 once the output is written
+    if ! execute_command "./YAMLtecture --detectCycles --configIn=$dir/config.yaml --out=$dir/cycles.txt || [ \$? -eq 1 ]" "$depth" "cycles.txt" "Generated" "no"; then
+        return 1
+    fi
+
+    return 0
+}
+
+# Function to regenerate analysis.yaml for the directories that include it
+# Arguments:
+#   $1 - Directory path
+#   $2 - Depth level
+process_analysis() {
+    local dir="${1%/}"
+    local depth=$2
+
+    [ ! -f "$dir/analysis.yaml" ] && return 0
+
+    if ! execute_command "./YAMLtecture --analyze --configIn=$dir/config.yaml --out=$dir/analysis.yaml" "$depth" "analysis.yaml" "Generated" "no"; then
+        return 1
+    fi
+
+    return 0
+}
+
 # Function to process queries within a directory
 # Arguments:
 #   $1 - Configuration directory path
@@ -218,6 +380,10 @@ process_queries() {
         fi
 
         process_mermaid "$query" "$((depth + 2))"
+        process_dot "$query" "$((depth + 2))"
+        process_plantuml "$query" "$((depth + 2))"
+        process_c4 "$query" "$((depth + 2))"
+        process_d2 "$query" "$((depth + 2))"
     done
 
     return $FAILURE
@@ -252,6 +418,30 @@ process_config_dir() {
     # Process mermaid if it exists
     [ -f "$dir/mermaid.yaml" ] && process_mermaid "$dir" "$((depth + 1))"
 
+    # Process dot if it exists
+    [ -f "$dir/dot.yaml" ] && process_dot "$dir" "$((depth + 1))"
+
+    # Process plantuml if it exists
+    [ -f "$dir/plantuml.yaml" ] && process_plantuml "$dir" "$((depth + 1))"
+
+    # Process c4 if it exists
+    [ -f "$dir/c4.yaml" ] && process_c4 "$dir" "$((depth + 1))"
+
+    # Process structurizr if it exists
+    [ -f "$dir/structurizr.yaml" ] && process_structurizr "$dir" "$((depth + 1))"
+
+    # Process d2 if it exists
+    [ -f "$dir/d2.yaml" ] && process_d2 "$dir" "$((depth + 1))"
+
+    # Process lint if it exists
+    [ -f "$dir/lint.yaml" ] && process_lint "$dir" "$((depth + 1))"
+
+    # Process cycles if they are tracked
+    [ -f "$dir/cycles.txt" ] && process_cycles "$dir" "$((depth + 1))"
+
+    # Process analysis if it is tracked
+    [ -f "$dir/analysis.yaml" ] && process_analysis "$dir" "$((depth + 1))"
+
     # Process queries if they exist
     [ -d "$dir/queries" ] && process_queries "$dir" "$((depth + 1))"
 }
@@ -293,7 +483,7 @@ process_invalid() {
     return 1
   fi
 
-  # Get a list of category directories (config, mermaid, query)
+  # Get a list of category directories (config, mermaid, query, lint, dot, plantuml, c4, structurizr, d2)
   local categories=($(find "$invalid_dir" -maxdepth 1 -mindepth 1 -type d -not -path '*/\.*'))
   
   if [ ${#categories[@]} -eq 0 ]; then
@@ -320,6 +510,24 @@ process_invalid() {
       "query")
         validation_command="--validateQuery"
         ;;
+      "lint")
+        validation_command="--validateLint"
+        ;;
+      "dot")
+        validation_command="--validateDot"
+        ;;
+      "plantuml")
+        validation_command="--validatePlantUML"
+        ;;
+      "c4")
+        validation_command="--validateC4"
+        ;;
+      "structurizr")
+        validation_command="--validateStructurizr"
+        ;;
+      "d2")
+        validation_command="--validateD2"
+        ;;
       *)
         echo -e "  ${RED}ERROR: Unknown category '$category_name'.${NC}"
         FAILURE=1
@@ -351,6 +559,12 @@ process_invalid() {
         echo -e "    ${RED}ERROR: No error output captured. The validation might have unexpectedly passed.${NC}"
         FAILURE=1
       fi
+
+      # Regenerate the JSON formatted errors for test cases that include them
+      local expected_json_file="$test_dir/expected_error.json"
+      if [ -f "$expected_json_file" ]; then
+        ./YAMLtecture $validation_command --${category_name}In="$input_file" --errorFormat=json > "$expected_json_file"
+      fi
     done
   done
 }
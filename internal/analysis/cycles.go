@@ -0,0 +1,190 @@
+package analysis
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
+)
+
+// Cycle is a group of nodes that depend on each other through links, the
+// strongly connected component of the link graph.
+type Cycle struct {
+	// The IDs of the nodes in the component in the order they are defined
+	Nodes []string
+	// The shortest path through the links that starts and ends at the first node
+	Path []string
+}
+
+// String returns the cycle as an ordered path, listing the rest of the
+// component if the path does not pass through all of its nodes.
+func (c Cycle) String() string {
+	line := strings.Join(c.Path, " -> ")
+	if len(c.Nodes) > len(c.Path)-1 {
+		line += fmt.Sprintf(" (component: %s)", strings.Join(c.Nodes, ", "))
+	}
+	return line
+}
+
+// Cycles holds the cycles found in a configuration.
+type Cycles []Cycle
+
+// String returns the cycles one per line followed by a summary.
+func (cycles Cycles) String() string {
+	var output strings.Builder
+	for _, cycle := range cycles {
+		output.WriteString("cycle: ")
+		output.WriteString(cycle.String())
+		output.WriteString("\n")
+	}
+	if len(cycles) > 0 {
+		output.WriteString("\n")
+	}
+	if len(cycles) == 1 {
+		output.WriteString("1 cycle found")
+	} else {
+		output.WriteString(fmt.Sprintf("%d cycles found", len(cycles)))
+	}
+	return output.String()
+}
+
+// FindCycles returns the cycles in the link graph of the configuration following
+// only links with a type in linkTypes unless linkTypes is empty. The cycles are
+// ordered by the first of their nodes defined in the configuration.
+func FindCycles(config *configuration.Config, linkTypes []string) Cycles {
+	ctx := query.NewConfigContext(config)
+
+	// Order the nodes in each component as they are defined in the configuration
+	order := make(map[string]int)
+	for i, node := range config.Nodes {
+		order[node.ID] = i
+	}
+
+	cycles := Cycles{}
+	for _, component := range stronglyConnected(config, linkTypes, ctx) {
+		// A single node is only a cycle if it links to itself
+		if len(component) == 1 && !slices.Contains(successors(component[0], linkTypes, ctx), component[0]) {
+			continue
+		}
+
+		slices.SortFunc(component, func(a, b string) int {
+			return order[a] - order[b]
+		})
+
+		cycles = append(cycles, Cycle{
+			Nodes: component,
+			Path:  shortestCycle(component, linkTypes, ctx),
+		})
+	}
+
+	slices.SortFunc(cycles, func(a, b Cycle) int {
+		return order[a.Nodes[0]] - order[b.Nodes[0]]
+	})
+
+	return cycles
+}
+
+// stronglyConnected returns the strongly connected components of the link graph
+// using Tarjan's algorithm, following only links with a type in linkTypes if set.
+// Every node is part of exactly one component.
+func stronglyConnected(config *configuration.Config, linkTypes []string, ctx *query.ConfigContext) [][]string {
+	index := 0
+	indexes := make(map[string]int)
+	lowLinks := make(map[string]int)
+	onStack := make(map[string]bool)
+	stack := []string{}
+	components := [][]string{}
+
+	var connect func(nodeID string)
+	connect = func(nodeID string) {
+		indexes[nodeID] = index
+		lowLinks[nodeID] = index
+		index++
+		stack = append(stack, nodeID)
+		onStack[nodeID] = true
+
+		for _, target := range successors(nodeID, linkTypes, ctx) {
+			if _, visited := indexes[target]; !visited {
+				connect(target)
+				lowLinks[nodeID] = min(lowLinks[nodeID], lowLinks[target])
+			} else if onStack[target] {
+				lowLinks[nodeID] = min(lowLinks[nodeID], indexes[target])
+			}
+		}
+
+		// The node is the root of a component, pop the component from the stack
+		if lowLinks[nodeID] == indexes[nodeID] {
+			component := []string{}
+			for {
+				last := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[last] = false
+				component = append(component, last)
+				if last == nodeID {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for _, node := range config.Nodes {
+		if _, visited := indexes[node.ID]; !visited {
+			connect(node.ID)
+		}
+	}
+
+	return components
+}
+
+// successors returns the IDs of the nodes targeted by the links from the node,
+// following only links with a type in linkTypes if set.
+func successors(nodeID string, linkTypes []string, ctx *query.ConfigContext) []string {
+	result := []string{}
+	for _, link := range ctx.OutboundLinks[nodeID] {
+		if query.LinkTypeAllowed(link, linkTypes) {
+			result = append(result, link.Target)
+		}
+	}
+	return result
+}
+
+// shortestCycle returns the shortest path within the component that starts and
+// ends at its first node.
+func shortestCycle(component []string, linkTypes []string, ctx *query.ConfigContext) []string {
+	start := component[0]
+	members := make(map[string]bool)
+	for _, nodeID := range component {
+		members[nodeID] = true
+	}
+
+	// Breadth-first search recording the previous node to rebuild the path
+	previous := make(map[string]string)
+	frontier := []string{start}
+	for len(frontier) > 0 {
+		next := []string{}
+		for _, current := range frontier {
+			for _, target := range successors(current, linkTypes, ctx) {
+				if target == start {
+					path := []string{}
+					for nodeID := current; nodeID != start; nodeID = previous[nodeID] {
+						path = append(path, nodeID)
+					}
+					path = append(path, start)
+					slices.Reverse(path)
+					return append(path, start)
+				}
+				if _, seen := previous[target]; seen || !members[target] {
+					continue
+				}
+				previous[target] = current
+				next = append(next, target)
+			}
+		}
+		frontier = next
+	}
+
+	return []string{start}
+}
@@ -0,0 +1,116 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+)
+
+func TestFindCycles(t *testing.T) {
+	err := filepath.Walk("../../tests", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			configPath := filepath.Join(path, "config.yaml")
+			cyclesPath := filepath.Join(path, "cycles.txt")
+
+			if _, err := os.Stat(configPath); os.IsNotExist(err) {
+				return nil
+			}
+			if _, err := os.Stat(cyclesPath); os.IsNotExist(err) {
+				return nil
+			}
+
+			relDir, err := filepath.Rel("../../tests", path)
+			if err != nil {
+				return err
+			}
+
+			sanitizedRelDir := strings.ReplaceAll(relDir, string(filepath.Separator), "#")
+
+			t.Run(sanitizedRelDir, func(t *testing.T) {
+				config, err := configuration.LoadConfig(configPath)
+				if err != nil {
+					t.Fatalf("Failed to load config: %v", err)
+				}
+
+				err = config.Validate()
+				if err != nil {
+					t.Fatalf("Failed to validate config: %v", err)
+				}
+
+				expectedBytes, err := os.ReadFile(cyclesPath)
+				if err != nil {
+					t.Fatalf("Failed to read cycles: %v", err)
+				}
+				expectedOutput := string(expectedBytes)
+
+				output := FindCycles(config, nil).String()
+				if output != expectedOutput {
+					t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
+				}
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error walking through example folder: %v", err)
+	}
+}
+
+func TestFindCyclesLinkTypes(t *testing.T) {
+	config, err := configuration.ParseYAML(`
+nodes:
+  - id: a
+    type: Service
+  - id: b
+    type: Service
+  - id: c
+    type: Service
+links:
+  - source: a
+    target: b
+    type: REST
+  - source: b
+    target: c
+    type: REST
+  - source: c
+    target: a
+    type: Publish
+  - source: c
+    target: b
+    type: gRPC
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		linkTypes []string
+		expected  []string
+	}{
+		{"all link types", nil, []string{"a -> b -> c -> a"}},
+		{"synchronous links", []string{"REST", "gRPC"}, []string{"b -> c -> b"}},
+		{"single link type", []string{"REST"}, []string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cycles := FindCycles(config, tc.linkTypes)
+
+			actual := []string{}
+			for _, cycle := range cycles {
+				actual = append(actual, cycle.String())
+			}
+
+			if strings.Join(actual, "\n") != strings.Join(tc.expected, "\n") {
+				t.Errorf("Expected cycles %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+}
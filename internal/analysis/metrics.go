@@ -0,0 +1,271 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
+)
+
+// maxRanked is the number of entries included in the ranked lists of the report
+const maxRanked = 5
+
+// Report contains the metrics computed from the links and hierarchy of a configuration.
+type Report struct {
+	// The totals for the whole configuration
+	Summary Summary `yaml:"summary" json:"summary"`
+	// The nodes without any links
+	Orphans []string `yaml:"orphans" json:"orphans"`
+	// The nodes without a parent
+	Roots []string `yaml:"roots" json:"roots"`
+	// The nodes without children
+	Leaves []string `yaml:"leaves" json:"leaves"`
+	// The nodes with the most nodes depending on them directly or indirectly
+	Hubs []Hub `yaml:"hubs" json:"hubs"`
+	// The longest chains of dependencies
+	LongestChains []Chain `yaml:"longestChains" json:"longestChains"`
+	// The metrics for each node in the order they are defined
+	Nodes []NodeMetrics `yaml:"nodes" json:"nodes"`
+}
+
+// Summary contains the totals for the whole configuration.
+type Summary struct {
+	Nodes        int            `yaml:"nodes" json:"nodes"`
+	Links        int            `yaml:"links" json:"links"`
+	LinkTypes    map[string]int `yaml:"linkTypes" json:"linkTypes"`
+	Orphans      int            `yaml:"orphans" json:"orphans"`
+	Roots        int            `yaml:"roots" json:"roots"`
+	Leaves       int            `yaml:"leaves" json:"leaves"`
+	MaxDepth     int            `yaml:"maxDepth" json:"maxDepth"`
+	LongestChain int            `yaml:"longestChain" json:"longestChain"`
+}
+
+// NodeMetrics contains the metrics for a single node.
+type NodeMetrics struct {
+	ID     string `yaml:"id" json:"id"`
+	Type   string `yaml:"type" json:"type"`
+	Parent string `yaml:"parent,omitempty" json:"parent,omitempty"`
+	// The depth in the parent hierarchy, nodes without a parent have a depth of 1
+	Depth int `yaml:"depth" json:"depth"`
+	// The number of direct children
+	Children int `yaml:"children" json:"children"`
+	// The number of links targeting the node (fan-in)
+	Inbound int `yaml:"inbound" json:"inbound"`
+	// The number of links originating from the node (fan-out)
+	Outbound int `yaml:"outbound" json:"outbound"`
+	// The inbound links counted by their type
+	InboundByType map[string]int `yaml:"inboundByType,omitempty" json:"inboundByType,omitempty"`
+	// The outbound links counted by their type
+	OutboundByType map[string]int `yaml:"outboundByType,omitempty" json:"outboundByType,omitempty"`
+	// The number of nodes that depend on the node directly or indirectly
+	Dependents int `yaml:"dependents" json:"dependents"`
+	// The number of nodes the node depends on directly or indirectly
+	Dependencies int `yaml:"dependencies" json:"dependencies"`
+}
+
+// Hub is a node that many other nodes depend on.
+type Hub struct {
+	ID         string `yaml:"id" json:"id"`
+	Dependents int    `yaml:"dependents" json:"dependents"`
+	Inbound    int    `yaml:"inbound" json:"inbound"`
+}
+
+// Chain is a path of dependencies following the links.
+type Chain struct {
+	// The number of links in the path
+	Length int `yaml:"length" json:"length"`
+	// The IDs of the nodes in the path
+	Path []string `yaml:"path" json:"path"`
+}
+
+// YamlString returns the YAML representation of the report
+func (r *Report) YamlString() string {
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return fmt.Sprintf("error marshalling report: %v", err)
+	}
+	return string(data)
+}
+
+// JSON returns the indented JSON representation of the report
+func (r *Report) JSON() string {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("error marshalling report: %v", err)
+	}
+	return string(data)
+}
+
+// Analyze computes the metrics for the configuration following only links with
+// a type in linkTypes unless linkTypes is empty.
+func Analyze(config *configuration.Config, linkTypes []string) *Report {
+	ctx := query.NewConfigContext(config)
+
+	report := &Report{
+		Summary: Summary{
+			Nodes:     len(config.Nodes),
+			LinkTypes: make(map[string]int),
+		},
+		Orphans:       []string{},
+		Roots:         []string{},
+		Leaves:        []string{},
+		Hubs:          []Hub{},
+		LongestChains: []Chain{},
+		Nodes:         []NodeMetrics{},
+	}
+
+	for i := range config.Links {
+		link := &config.Links[i]
+		if query.LinkTypeAllowed(link, linkTypes) {
+			report.Summary.Links++
+			report.Summary.LinkTypes[link.Type]++
+		}
+	}
+
+	for _, node := range config.Nodes {
+		metrics := NodeMetrics{
+			ID:             node.ID,
+			Type:           node.Type,
+			Parent:         node.Parent,
+			Depth:          ctx.Depth(node.ID),
+			Children:       len(ctx.ChildrenMap[node.ID]),
+			InboundByType:  countByType(ctx.InboundLinks[node.ID], linkTypes),
+			OutboundByType: countByType(ctx.OutboundLinks[node.ID], linkTypes),
+			Dependents:     len(ctx.Reachable(node.ID, query.DirectionIn, linkTypes, 0)),
+			Dependencies:   len(ctx.Reachable(node.ID, query.DirectionOut, linkTypes, 0)),
+		}
+		for _, count := range metrics.InboundByType {
+			metrics.Inbound += count
+		}
+		for _, count := range metrics.OutboundByType {
+			metrics.Outbound += count
+		}
+
+		if metrics.Inbound == 0 && metrics.Outbound == 0 {
+			report.Orphans = append(report.Orphans, node.ID)
+		}
+		if node.Parent == "" {
+			report.Roots = append(report.Roots, node.ID)
+		}
+		if metrics.Children == 0 {
+			report.Leaves = append(report.Leaves, node.ID)
+		}
+		report.Summary.MaxDepth = max(report.Summary.MaxDepth, metrics.Depth)
+
+		report.Nodes = append(report.Nodes, metrics)
+	}
+
+	report.Summary.Orphans = len(report.Orphans)
+	report.Summary.Roots = len(report.Roots)
+	report.Summary.Leaves = len(report.Leaves)
+
+	// Rank the nodes by their dependents keeping the order they are defined for ties
+	ranked := slices.Clone(report.Nodes)
+	slices.SortStableFunc(ranked, func(a, b NodeMetrics) int {
+		if a.Dependents != b.Dependents {
+			return b.Dependents - a.Dependents
+		}
+		return b.Inbound - a.Inbound
+	})
+	for _, metrics := range ranked {
+		if metrics.Dependents == 0 || len(report.Hubs) == maxRanked {
+			break
+		}
+		report.Hubs = append(report.Hubs, Hub{ID: metrics.ID, Dependents: metrics.Dependents, Inbound: metrics.Inbound})
+	}
+
+	report.LongestChains = longestChains(config, linkTypes, ctx)
+	if len(report.LongestChains) > 0 {
+		report.Summary.LongestChain = report.LongestChains[0].Length
+	}
+
+	return report
+}
+
+// longestChains returns the longest dependency chains starting from the nodes
+// no other node depends on. Links between the nodes of a cycle are not followed
+// so every chain is finite.
+func longestChains(config *configuration.Config, linkTypes []string, ctx *query.ConfigContext) []Chain {
+	componentOf := make(map[string]int)
+	for i, component := range stronglyConnected(config, linkTypes, ctx) {
+		for _, nodeID := range component {
+			componentOf[nodeID] = i
+		}
+	}
+
+	// Only the links that leave a cycle are followed
+	next := func(nodeID string) []string {
+		result := []string{}
+		for _, target := range successors(nodeID, linkTypes, ctx) {
+			if componentOf[target] != componentOf[nodeID] {
+				result = append(result, target)
+			}
+		}
+		return result
+	}
+
+	// The longest path from each node, the first successor wins ties
+	longest := make(map[string][]string)
+	var pathFrom func(nodeID string) []string
+	pathFrom = func(nodeID string) []string {
+		if path, exists := longest[nodeID]; exists {
+			return path
+		}
+		best := []string{}
+		for _, target := range next(nodeID) {
+			if path := pathFrom(target); len(path) > len(best) {
+				best = path
+			}
+		}
+		path := append([]string{nodeID}, best...)
+		longest[nodeID] = path
+		return path
+	}
+
+	// Chains start at the nodes without dependents outside of their own cycle
+	hasDependents := make(map[string]bool)
+	for _, node := range config.Nodes {
+		for _, target := range next(node.ID) {
+			hasDependents[target] = true
+		}
+	}
+
+	chains := []Chain{}
+	for _, node := range config.Nodes {
+		if hasDependents[node.ID] {
+			continue
+		}
+		if path := pathFrom(node.ID); len(path) > 1 {
+			chains = append(chains, Chain{Length: len(path) - 1, Path: path})
+		}
+	}
+
+	slices.SortStableFunc(chains, func(a, b Chain) int {
+		return b.Length - a.Length
+	})
+	if len(chains) > maxRanked {
+		chains = chains[:maxRanked]
+	}
+
+	return chains
+}
+
+// countByType counts the links by their type, following only links with a type
+// in linkTypes if set. Returns nil if there are no links.
+func countByType(links []*configuration.Link, linkTypes []string) map[string]int {
+	var counts map[string]int
+	for _, link := range links {
+		if !query.LinkTypeAllowed(link, linkTypes) {
+			continue
+		}
+		if counts == nil {
+			counts = make(map[string]int)
+		}
+		counts[link.Type]++
+	}
+	return counts
+}
@@ -0,0 +1,63 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+)
+
+func TestAnalyze(t *testing.T) {
+	err := filepath.Walk("../../tests", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			configPath := filepath.Join(path, "config.yaml")
+			analysisPath := filepath.Join(path, "analysis.yaml")
+
+			if _, err := os.Stat(configPath); os.IsNotExist(err) {
+				return nil
+			}
+			if _, err := os.Stat(analysisPath); os.IsNotExist(err) {
+				return nil
+			}
+
+			relDir, err := filepath.Rel("../../tests", path)
+			if err != nil {
+				return err
+			}
+
+			sanitizedRelDir := strings.ReplaceAll(relDir, string(filepath.Separator), "#")
+
+			t.Run(sanitizedRelDir, func(t *testing.T) {
+				config, err := configuration.LoadConfig(configPath)
+				if err != nil {
+					t.Fatalf("Failed to load config: %v", err)
+				}
+
+				err = config.Validate()
+				if err != nil {
+					t.Fatalf("Failed to validate config: %v", err)
+				}
+
+				expectedBytes, err := os.ReadFile(analysisPath)
+				if err != nil {
+					t.Fatalf("Failed to read analysis: %v", err)
+				}
+				expectedOutput := string(expectedBytes)
+
+				output := Analyze(config, nil).YamlString()
+				if output != expectedOutput {
+					t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
+				}
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error walking through example folder: %v", err)
+	}
+}
@@ -0,0 +1,398 @@
+package c4
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/diagram"
+	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
+)
+
+const (
+	ViewContext   = "context"
+	ViewContainer = "container"
+	ViewComponent = "component"
+)
+
+const (
+	levelPerson    = "Person"
+	levelSystem    = "System"
+	levelContainer = "Container"
+	levelComponent = "Component"
+)
+
+// levelRanks is the view a level first appears in, people and systems are shown
+// in every view while components are only shown in the component view
+var levelRanks = map[string]int{
+	levelPerson:    1,
+	levelSystem:    1,
+	levelContainer: 2,
+	levelComponent: 3,
+}
+
+// viewRanks is the deepest level rank shown in each view
+var viewRanks = map[string]int{
+	ViewContext:   1,
+	ViewContainer: 2,
+	ViewComponent: 3,
+}
+
+// viewDiagrams is the Mermaid diagram type for each view
+var viewDiagrams = map[string]string{
+	ViewContext:   "C4Context",
+	ViewContainer: "C4Container",
+	ViewComponent: "C4Component",
+}
+
+// C4 contains the settings for generating a C4 model view.
+type C4 struct {
+	// The view to generate (context, container, component)
+	View string `yaml:"view"`
+	// The title of the diagram (if set)
+	Title string `yaml:"title,omitempty"`
+	// The attribute to use as the element label (if set)
+	NodeLabel string `yaml:"nodeLabel,omitempty"`
+	// The attribute to use as the element description (if set)
+	NodeDescription string `yaml:"nodeDescription,omitempty"`
+	// The attribute to use as the technology of containers and components (if set)
+	NodeTechnology string `yaml:"nodeTechnology,omitempty"`
+	// The attribute to use as the technology of relationships (if set)
+	LinkTechnology string `yaml:"linkTechnology,omitempty"`
+	// The query limiting the configuration to the nodes and links in scope for the view
+	Scope query.Query `yaml:"scope,omitempty"`
+	// The queries to identify the nodes at each C4 level
+	Levels `yaml:",inline"`
+	// The queries to identify the elements drawn as a database or a queue
+	DatabaseNodes query.Nodes `yaml:"databaseNodes,omitempty"`
+	QueueNodes    query.Nodes `yaml:"queueNodes,omitempty"`
+	// The query to identify the elements outside of the system being described
+	ExternalNodes query.Nodes `yaml:"externalNodes,omitempty"`
+}
+
+// Levels contains the queries to identify the nodes at each C4 level.
+type Levels struct {
+	PersonNodes    query.Nodes `yaml:"personNodes,omitempty"`
+	SystemNodes    query.Nodes `yaml:"systemNodes,omitempty"`
+	ContainerNodes query.Nodes `yaml:"containerNodes,omitempty"`
+	ComponentNodes query.Nodes `yaml:"componentNodes,omitempty"`
+}
+
+// element is a node shown in the view with the elements nested inside of it.
+type element struct {
+	ID       string
+	Level    string
+	Children []*element
+}
+
+// GenerateC4 creates a Mermaid C4 diagram for the view from the config and C4
+// settings. Nodes below the level of the view are not shown, their links are
+// drawn from their nearest ancestor that is shown instead.
+func GenerateC4(config *configuration.Config, setting *C4) (string, error) {
+	var c4 strings.Builder
+
+	// Limit the configuration to the scope of the view.
+	scoped, err := query.ExecuteQuery(&setting.Scope, config)
+	if err != nil {
+		return "", fmt.Errorf("error executing scope query: %v", err)
+	}
+
+	// Namespaced IDs are rewritten as aliases and cannot collide with another ID.
+	if err := diagram.CheckIDs(&scoped, c4ID); err != nil {
+		return "", err
+	}
+
+	// Determine the level of each node.
+	levels, err := setting.Levels.assign(&scoped)
+	if err != nil {
+		return "", err
+	}
+
+	databases, err := matchNodes(setting.DatabaseNodes, &scoped)
+	if err != nil {
+		return "", fmt.Errorf("error executing database query: %v", err)
+	}
+	queues, err := matchNodes(setting.QueueNodes, &scoped)
+	if err != nil {
+		return "", fmt.Errorf("error executing queue query: %v", err)
+	}
+	externals, err := matchNodes(setting.ExternalNodes, &scoped)
+	if err != nil {
+		return "", fmt.Errorf("error executing external query: %v", err)
+	}
+
+	// Build a lookup for nodes.
+	nodeLookup := make(map[string]configuration.Node)
+	for _, node := range scoped.Nodes {
+		nodeLookup[node.ID] = node
+	}
+
+	// Only the nodes with a level up to the level of the view are shown.
+	viewRank := viewRanks[setting.View]
+	shown := make(map[string]*element)
+	for _, node := range scoped.Nodes {
+		if level, exists := levels[node.ID]; exists && levelRanks[level] <= viewRank {
+			shown[node.ID] = &element{ID: node.ID, Level: level}
+		}
+	}
+
+	// Helper: find the nearest shown node starting from the given node.
+	findShown := func(start string) string {
+		for cur := start; cur != ""; cur = nodeLookup[cur].Parent {
+			if shown[cur] != nil {
+				return cur
+			}
+		}
+		return ""
+	}
+
+	// Place the elements inside of their nearest shown ancestor.
+	var topLevel []*element
+	for _, node := range scoped.Nodes {
+		e, isShown := shown[node.ID]
+		if !isShown {
+			continue
+		}
+		if ancestor := findShown(node.Parent); ancestor != "" {
+			shown[ancestor].Children = append(shown[ancestor].Children, e)
+		} else {
+			topLevel = append(topLevel, e)
+		}
+	}
+
+	// Sort the elements for deterministic output.
+	sortElements(topLevel)
+
+	// Write the header.
+	c4.WriteString(viewDiagrams[setting.View])
+	c4.WriteString("\n")
+	if setting.Title != "" {
+		c4.WriteString(fmt.Sprintf("    title %s\n", setting.Title))
+	}
+
+	// Helper: the arguments of an element after its alias.
+	arguments := func(e *element, boundary bool) []string {
+		node := nodeLookup[e.ID]
+		label := e.ID
+		if val := configuration.NodeAttributeLabel(node, setting.NodeLabel); val != "" {
+			label = val
+		}
+		args := []string{label}
+		if boundary {
+			return args
+		}
+		if e.Level == levelContainer || e.Level == levelComponent {
+			args = append(args, configuration.NodeAttributeLabel(node, setting.NodeTechnology))
+		}
+		return append(args, configuration.NodeAttributeLabel(node, setting.NodeDescription))
+	}
+
+	c4.WriteString("\n")
+
+	// Recursive helper to output an element or a boundary around its children.
+	var outputElement func(e *element, indent string)
+	outputElement = func(e *element, indent string) {
+		if len(e.Children) == 0 {
+			macro := e.Level
+			if e.Level != levelPerson {
+				if databases[e.ID] {
+					macro += "Db"
+				} else if queues[e.ID] {
+					macro += "Queue"
+				}
+			}
+			if externals[e.ID] || nodeLookup[e.ID].External {
+				macro += "_Ext"
+			}
+			c4.WriteString(fmt.Sprintf("%s%s\n", indent, formatMacro(macro, []string{c4ID(e.ID)}, arguments(e, false))))
+			return
+		}
+
+		// Mermaid has no external variant of the system and container boundaries
+		macro := boundaryMacro(e.Level)
+		if externals[e.ID] || nodeLookup[e.ID].External {
+			macro = "Enterprise_Boundary"
+		}
+		c4.WriteString(fmt.Sprintf("%s%s {\n", indent, formatMacro(macro, []string{c4ID(e.ID)}, arguments(e, true))))
+		for _, child := range e.Children {
+			outputElement(child, indent+"    ")
+		}
+		c4.WriteString(fmt.Sprintf("%s}\n", indent))
+	}
+
+	for _, e := range topLevel {
+		outputElement(e, "    ")
+	}
+
+	// Helper: whether the element is nested inside of the boundary.
+	isInside := func(id string, boundary string) bool {
+		for cur := findShown(nodeLookup[id].Parent); cur != ""; cur = findShown(nodeLookup[cur].Parent) {
+			if cur == boundary {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Draw the links between the nearest shown nodes, skipping the links inside
+	// of a single element and the duplicates this produces. Mermaid cannot draw a
+	// relationship to a boundary so the links to an element drawn as a boundary
+	// are drawn to each of the elements inside of it instead.
+	type relationship struct {
+		Source     string
+		Target     string
+		Type       string
+		Technology string
+	}
+	var relationships []relationship
+	seen := make(map[string]bool)
+	for _, link := range scoped.Links {
+		source := findShown(link.Source)
+		target := findShown(link.Target)
+		if source == "" || target == "" || source == target {
+			continue
+		}
+		if isInside(source, target) || isInside(target, source) {
+			continue
+		}
+
+		technology := ""
+		if setting.LinkTechnology != "" {
+			if val, exists := configuration.LookupAttribute(link.Attributes, setting.LinkTechnology); exists {
+				technology = configuration.FormatAttributeValue(val)
+			}
+		}
+
+		for _, from := range leaves(shown[source]) {
+			for _, to := range leaves(shown[target]) {
+				key := from + "|" + to + "|" + link.Type
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				relationships = append(relationships, relationship{Source: from, Target: to, Type: link.Type, Technology: technology})
+			}
+		}
+	}
+
+	// Output the relationships sorted by their source and target.
+	sort.SliceStable(relationships, func(i, j int) bool {
+		if relationships[i].Source == relationships[j].Source {
+			return relationships[i].Target < relationships[j].Target
+		}
+		return relationships[i].Source < relationships[j].Source
+	})
+
+	if len(relationships) > 0 {
+		c4.WriteString("\n")
+	}
+	for _, rel := range relationships {
+		aliases := []string{c4ID(rel.Source), c4ID(rel.Target)}
+		c4.WriteString(fmt.Sprintf("    %s\n", formatMacro("Rel", aliases, []string{rel.Type, rel.Technology})))
+	}
+
+	return c4.String(), nil
+}
+
+// assign returns the level of each node in the config, a node matching more
+// than one level is assigned the first level that matches.
+func (l *Levels) assign(config *configuration.Config) (map[string]string, error) {
+	levels := make(map[string]string)
+	levelQueries := []struct {
+		level string
+		nodes query.Nodes
+	}{
+		{levelPerson, l.PersonNodes},
+		{levelSystem, l.SystemNodes},
+		{levelContainer, l.ContainerNodes},
+		{levelComponent, l.ComponentNodes},
+	}
+	for _, levelQuery := range levelQueries {
+		matched, err := matchNodes(levelQuery.nodes, config)
+		if err != nil {
+			return nil, fmt.Errorf("error executing %s query: %v", strings.ToLower(levelQuery.level), err)
+		}
+		for nodeID := range matched {
+			if _, exists := levels[nodeID]; !exists {
+				levels[nodeID] = levelQuery.level
+			}
+		}
+	}
+	return levels, nil
+}
+
+// matchNodes returns the IDs of the nodes matching the filters, an empty query
+// matches no nodes.
+func matchNodes(nodes query.Nodes, config *configuration.Config) (map[string]bool, error) {
+	matched := make(map[string]bool)
+	if len(nodes.Filters) == 0 {
+		return matched, nil
+	}
+
+	result, err := query.ExecuteQuery(&query.Query{Nodes: nodes}, config)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range result.Nodes {
+		matched[node.ID] = true
+	}
+	return matched, nil
+}
+
+// boundaryMacro returns the macro for an element drawn as a boundary around the
+// elements nested inside of it.
+func boundaryMacro(level string) string {
+	switch level {
+	case levelSystem:
+		return "System_Boundary"
+	case levelContainer:
+		return "Container_Boundary"
+	default:
+		return "Boundary"
+	}
+}
+
+// formatMacro returns a C4 macro such as 'Container(api, "API", "Go", "Serves orders")'.
+// The aliases are written as is while the other arguments are quoted, trailing
+// empty arguments are omitted.
+func formatMacro(macro string, aliases []string, args []string) string {
+	for len(args) > 0 && args[len(args)-1] == "" {
+		args = args[:len(args)-1]
+	}
+
+	parts := append([]string{}, aliases...)
+	for _, arg := range args {
+		parts = append(parts, fmt.Sprintf("\"%s\"", diagram.ReplaceQuotes(arg)))
+	}
+	return fmt.Sprintf("%s(%s)", macro, strings.Join(parts, ", "))
+}
+
+// c4ID returns the node ID in a form Mermaid accepts as an alias, replacing the
+// '/' in namespaced IDs such as 'payments/api' with '__'.
+func c4ID(id string) string {
+	return strings.ReplaceAll(id, configuration.NamespaceSeparator, "__")
+}
+
+// leaves returns the IDs of the elements drawn inside of the element that are not
+// boundaries themselves, or the ID of the element if it is not a boundary.
+func leaves(e *element) []string {
+	if len(e.Children) == 0 {
+		return []string{e.ID}
+	}
+	var ids []string
+	for _, child := range e.Children {
+		ids = append(ids, leaves(child)...)
+	}
+	return ids
+}
+
+// sortElements sorts the elements and their nested elements by ID.
+func sortElements(elements []*element) {
+	sort.Slice(elements, func(i, j int) bool {
+		return elements[i].ID < elements[j].ID
+	})
+	for _, e := range elements {
+		sortElements(e.Children)
+	}
+}
@@ -0,0 +1,179 @@
+package c4
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+)
+
+func TestGenerateC4(t *testing.T) {
+	err := filepath.Walk("../../tests", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			configPath := filepath.Join(path, "config.yaml")
+			c4ConfigPath := filepath.Join(path, "c4.yaml")
+			c4Path := filepath.Join(path, "c4.mmd")
+
+			if _, err := os.Stat(configPath); os.IsNotExist(err) {
+				return nil
+			}
+			if _, err := os.Stat(c4ConfigPath); os.IsNotExist(err) {
+				return nil
+			}
+			if _, err := os.Stat(c4Path); os.IsNotExist(err) {
+				return nil
+			}
+
+			relDir, err := filepath.Rel("../../tests", path)
+			if err != nil {
+				return err
+			}
+
+			sanitizedRelDir := strings.ReplaceAll(relDir, string(filepath.Separator), "#")
+
+			t.Run(sanitizedRelDir, func(t *testing.T) {
+				config, err := configuration.LoadConfig(configPath)
+				if err != nil {
+					t.Fatalf("Failed to load config: %v", err)
+				}
+
+				c4Config, err := LoadC4(c4ConfigPath)
+				if err != nil {
+					t.Fatalf("Failed to load c4 config: %v", err)
+				}
+
+				err = c4Config.Validate()
+				if err != nil {
+					t.Fatalf("C4 config validation failed: %v", err)
+				}
+
+				expectedBytes, err := os.ReadFile(c4Path)
+				if err != nil {
+					t.Fatalf("Failed to read C4 file: %v", err)
+				}
+				expectedOutput := string(expectedBytes)
+
+				output, err := GenerateC4(config, c4Config)
+				if err != nil {
+					t.Fatalf("GenerateC4 returned error: %v", err)
+				}
+				if output != expectedOutput {
+					t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
+				}
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error walking through example folder: %v", err)
+	}
+}
+
+func TestGenerateC4Scope(t *testing.T) {
+	config, err := configuration.ParseYAML(`
+nodes:
+  - id: user
+    type: Person
+  - id: shop
+    type: System
+  - id: api
+    type: Container
+    parent: shop
+  - id: db
+    type: Container
+    parent: shop
+  - id: reporting
+    type: System
+links:
+  - source: user
+    target: api
+    type: Uses
+  - source: api
+    target: db
+    type: Reads
+  - source: reporting
+    target: db
+    type: Reads
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	setting, err := ParseYAML(`
+scope:
+  nodes:
+    filters:
+      - condition:
+          field: id
+          operator: notEquals
+          value: reporting
+personNodes:
+  filters:
+    - condition:
+        field: type
+        operator: equals
+        value: Person
+systemNodes:
+  filters:
+    - condition:
+        field: type
+        operator: equals
+        value: System
+containerNodes:
+  filters:
+    - condition:
+        field: type
+        operator: equals
+        value: Container
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse settings: %v", err)
+	}
+
+	output, err := GenerateC4(config, setting)
+	if err != nil {
+		t.Fatalf("GenerateC4 returned error: %v", err)
+	}
+
+	// The containers are not shown in the context view so the link to the API is
+	// drawn to the system and the link between the containers is not drawn
+	expected := `C4Context
+
+    System(shop, "shop")
+    Person(user, "user")
+
+    Rel(user, shop, "Uses")
+`
+	if output != expected {
+		t.Errorf("Expected output:\n%s\nGot:\n%s", expected, output)
+	}
+}
+
+func TestGenerateC4IDCollision(t *testing.T) {
+	config, err := configuration.ParseYAML(`
+nodes:
+  - id: payments/api
+    type: System
+  - id: payments__api
+    type: System
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	setting, err := ParseYAML(`view: context`)
+	if err != nil {
+		t.Fatalf("Failed to parse settings: %v", err)
+	}
+
+	_, err = GenerateC4(config, setting)
+	expected := "node IDs 'payments/api' and 'payments__api' are both rendered as 'payments__api'"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, got: %v", expected, err)
+	}
+}
@@ -0,0 +1,99 @@
+package c4
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseYAML parses the YAML content into a C4
+func ParseYAML(content string) (*C4, error) {
+	var config C4
+	err := yaml.Unmarshal([]byte(content), &config)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling YAML: %v", err)
+	}
+
+	// Specify the default values if they were not provided
+
+	if config.View == "" {
+		config.View = ViewContext
+	}
+
+	return &config, nil
+}
+
+// LoadC4 loads and parses a single YAML C4 setting file from the given path.
+func LoadC4(filePath string) (*C4, error) {
+
+	// Read the file contents to a string
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %v", err)
+	}
+
+	// Parse the YAML
+	return ParseYAML(string(data))
+}
+
+// ParseStructurizrYAML parses the YAML content into a Structurizr
+func ParseStructurizrYAML(content string) (*Structurizr, error) {
+	var config Structurizr
+	err := yaml.Unmarshal([]byte(content), &config)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling YAML: %v", err)
+	}
+
+	return &config, nil
+}
+
+// LoadStructurizr loads and parses a single YAML Structurizr setting file from the given path.
+func LoadStructurizr(filePath string) (*Structurizr, error) {
+
+	// Read the file contents to a string
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %v", err)
+	}
+
+	// Parse the YAML
+	config, err := ParseStructurizrYAML(string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the query files of the views relative to the settings file
+	if err := config.LoadQueries(filepath.Dir(filePath)); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// LoadQueries loads the query files referenced by the views with 'queryIn' as
+// their query, resolving relative paths against the given directory.
+func (s *Structurizr) LoadQueries(dir string) error {
+	for i := range s.Views {
+		view := &s.Views[i]
+		if view.QueryIn == "" {
+			continue
+		}
+		if !view.Query.IsEmpty() {
+			return fmt.Errorf("view '%s' cannot set both 'query' and 'queryIn'", view.Key)
+		}
+
+		queryPath := view.QueryIn
+		if !filepath.IsAbs(queryPath) {
+			queryPath = filepath.Join(dir, queryPath)
+		}
+		loaded, err := query.LoadQuery(queryPath)
+		if err != nil {
+			return fmt.Errorf("error loading query for view '%s': %v", view.Key, err)
+		}
+		view.Query = *loaded
+	}
+	return nil
+}
@@ -0,0 +1,346 @@
+package c4
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/diagram"
+	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
+)
+
+const (
+	ViewSystemLandscape = "systemLandscape"
+	ViewSystemContext   = "systemContext"
+)
+
+// structurizrViewRanks is the deepest level rank that can be included in each
+// Structurizr view, using the same ranks as the C4 views
+var structurizrViewRanks = map[string]int{
+	ViewSystemLandscape: 1,
+	ViewSystemContext:   1,
+	ViewContainer:       2,
+	ViewComponent:       3,
+}
+
+// structurizrScopeLevels is the level of the element each Structurizr view is scoped to
+var structurizrScopeLevels = map[string]string{
+	ViewSystemContext: levelSystem,
+	ViewContainer:     levelSystem,
+	ViewComponent:     levelContainer,
+}
+
+// structurizrKeywords is the DSL keyword for the elements at each level
+var structurizrKeywords = map[string]string{
+	levelPerson:    "person",
+	levelSystem:    "softwareSystem",
+	levelContainer: "container",
+	levelComponent: "component",
+}
+
+// invalidIdentifierRegex matches the characters not allowed in a Structurizr identifier
+var invalidIdentifierRegex = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// Structurizr contains the settings for exporting the config as a Structurizr workspace.
+type Structurizr struct {
+	// The name of the workspace (if set)
+	Name string `yaml:"name,omitempty"`
+	// The description of the workspace (if set)
+	Description string `yaml:"description,omitempty"`
+	// The attribute to use as the element name (if set)
+	NodeLabel string `yaml:"nodeLabel,omitempty"`
+	// The attribute to use as the element description (if set)
+	NodeDescription string `yaml:"nodeDescription,omitempty"`
+	// The attribute to use as the technology of containers and components (if set)
+	NodeTechnology string `yaml:"nodeTechnology,omitempty"`
+	// The attribute to use as the technology of relationships (if set)
+	LinkTechnology string `yaml:"linkTechnology,omitempty"`
+	// The attributes added as tags to the elements in addition to their type
+	TagAttributes []string `yaml:"tagAttributes,omitempty"`
+	// The queries to identify the nodes at each C4 level
+	Levels `yaml:",inline"`
+	// The views to define in the workspace
+	Views []View `yaml:"views,omitempty"`
+}
+
+// View is a Structurizr view of the workspace.
+type View struct {
+	// The unique key of the view
+	Key string `yaml:"key"`
+	// The type of view (systemLandscape, systemContext, container, component)
+	Type string `yaml:"type"`
+	// The ID of the node the view is scoped to, required for all but the system landscape
+	Scope string `yaml:"scope,omitempty"`
+	// The description of the view (if set)
+	Description string `yaml:"description,omitempty"`
+	// The query selecting the elements included in the view, all elements are included if not set
+	Query query.Query `yaml:"query,omitempty"`
+	// The path of a query file to use as the query, relative to the settings file
+	QueryIn string `yaml:"queryIn,omitempty"`
+	// The direction of the automatic layout (tb, bt, lr, rl)
+	AutoLayout string `yaml:"autoLayout,omitempty"`
+}
+
+// GenerateStructurizr creates a Structurizr workspace DSL from the config and
+// Structurizr settings. Nodes without a level are not exported, the elements
+// nested inside of them are nested in their nearest exported ancestor instead.
+func GenerateStructurizr(config *configuration.Config, setting *Structurizr) (string, error) {
+	var dsl strings.Builder
+
+	// IDs are rewritten as identifiers and cannot collide with another ID.
+	if err := diagram.CheckIDs(config, structurizrID); err != nil {
+		return "", err
+	}
+
+	// Determine the level of each node.
+	levels, err := setting.Levels.assign(config)
+	if err != nil {
+		return "", err
+	}
+
+	// Build a lookup for nodes.
+	nodeLookup := make(map[string]configuration.Node)
+	for _, node := range config.Nodes {
+		nodeLookup[node.ID] = node
+	}
+
+	exported := make(map[string]*element)
+	for _, node := range config.Nodes {
+		if level, exists := levels[node.ID]; exists {
+			exported[node.ID] = &element{ID: node.ID, Level: level}
+		}
+	}
+
+	// Helper: find the nearest exported node starting from the given node.
+	findExported := func(start string) string {
+		for cur := start; cur != ""; cur = nodeLookup[cur].Parent {
+			if exported[cur] != nil {
+				return cur
+			}
+		}
+		return ""
+	}
+
+	// Nest the elements inside of their nearest exported ancestor, the nesting
+	// must follow the C4 levels.
+	var topLevel []*element
+	for _, node := range config.Nodes {
+		e, isExported := exported[node.ID]
+		if !isExported {
+			continue
+		}
+
+		ancestor := findExported(node.Parent)
+		parentLevel := ""
+		if ancestor != "" {
+			parentLevel = exported[ancestor].Level
+		}
+		if err := checkNesting(e, parentLevel); err != nil {
+			return "", err
+		}
+
+		if ancestor != "" {
+			exported[ancestor].Children = append(exported[ancestor].Children, e)
+		} else {
+			topLevel = append(topLevel, e)
+		}
+	}
+
+	// Sort the elements for deterministic output.
+	sortElements(topLevel)
+
+	// Write the header.
+	dsl.WriteString(fmt.Sprintf("workspace%s {\n", formatArguments([]string{setting.Name, setting.Description})))
+	dsl.WriteString("\n")
+	dsl.WriteString("    model {\n")
+
+	// Helper: the tags of an element from its type and the tag attributes. Tags
+	// are separated by commas so the commas inside of a tag, such as in the
+	// 'alice, bob' of a list value, are replaced with semicolons.
+	tags := func(node configuration.Node) string {
+		values := []string{node.Type}
+		for _, key := range setting.TagAttributes {
+			if val := configuration.NodeAttributeLabel(node, key); val != "" {
+				values = append(values, fmt.Sprintf("%s:%s", key, val))
+			}
+		}
+		for i := range values {
+			values[i] = strings.ReplaceAll(values[i], ",", ";")
+		}
+		return strings.Join(values, ",")
+	}
+
+	// Recursive helper to output an element and the elements nested inside of it.
+	var outputElement func(e *element, indent string)
+	outputElement = func(e *element, indent string) {
+		node := nodeLookup[e.ID]
+		name := e.ID
+		if val := configuration.NodeAttributeLabel(node, setting.NodeLabel); val != "" {
+			name = val
+		}
+		args := []string{name, configuration.NodeAttributeLabel(node, setting.NodeDescription)}
+		if e.Level == levelContainer || e.Level == levelComponent {
+			args = append(args, configuration.NodeAttributeLabel(node, setting.NodeTechnology))
+		}
+		args = append(args, tags(node))
+
+		statement := fmt.Sprintf("%s%s = %s%s", indent, structurizrID(e.ID), structurizrKeywords[e.Level], formatArguments(args))
+		if len(e.Children) == 0 {
+			dsl.WriteString(statement + "\n")
+			return
+		}
+
+		dsl.WriteString(statement + " {\n")
+		for _, child := range e.Children {
+			outputElement(child, indent+"    ")
+		}
+		dsl.WriteString(fmt.Sprintf("%s}\n", indent))
+	}
+
+	for _, e := range topLevel {
+		outputElement(e, "        ")
+	}
+
+	// Output the relationships between the nearest exported nodes sorted by their
+	// source and target, skipping the links inside of a single element.
+	links := diagram.SortLinks(config.Links)
+
+	var relationships []string
+	seen := make(map[string]bool)
+	for _, link := range links {
+		source := findExported(link.Source)
+		target := findExported(link.Target)
+		if source == "" || target == "" || source == target {
+			continue
+		}
+
+		technology := ""
+		if setting.LinkTechnology != "" {
+			if val, exists := configuration.LookupAttribute(link.Attributes, setting.LinkTechnology); exists {
+				technology = configuration.FormatAttributeValue(val)
+			}
+		}
+
+		relationship := fmt.Sprintf("        %s -> %s%s\n", structurizrID(source), structurizrID(target), formatArguments([]string{link.Type, technology, link.Type}))
+		if seen[relationship] {
+			continue
+		}
+		seen[relationship] = true
+		relationships = append(relationships, relationship)
+	}
+
+	if len(relationships) > 0 {
+		dsl.WriteString("\n")
+	}
+	for _, relationship := range relationships {
+		dsl.WriteString(relationship)
+	}
+
+	dsl.WriteString("    }\n")
+
+	// Output the views in the order they are defined.
+	dsl.WriteString("\n")
+	dsl.WriteString("    views {\n")
+	for i, view := range setting.Views {
+		if i > 0 {
+			dsl.WriteString("\n")
+		}
+
+		scope := ""
+		if level, required := structurizrScopeLevels[view.Type]; required {
+			if exported[view.Scope] == nil || exported[view.Scope].Level != level {
+				return "", fmt.Errorf("view '%s' must be scoped to a %s but '%s' is not", view.Key, structurizrKeywords[level], view.Scope)
+			}
+			scope = " " + structurizrID(view.Scope)
+		}
+
+		include, err := viewIncludes(view, config, levels)
+		if err != nil {
+			return "", fmt.Errorf("error executing query for view '%s': %v", view.Key, err)
+		}
+
+		dsl.WriteString(fmt.Sprintf("        %s%s%s {\n", view.Type, scope, formatArguments([]string{view.Key, view.Description})))
+		dsl.WriteString(fmt.Sprintf("            include %s\n", include))
+		if view.AutoLayout != "" {
+			dsl.WriteString(fmt.Sprintf("            autoLayout %s\n", view.AutoLayout))
+		}
+		dsl.WriteString("        }\n")
+	}
+	dsl.WriteString("    }\n")
+
+	dsl.WriteString("}\n")
+
+	return dsl.String(), nil
+}
+
+// checkNesting returns an error if the element cannot be nested in an element
+// of the parent level, an empty parent level is the top level of the model.
+func checkNesting(e *element, parentLevel string) error {
+	allowed := ""
+	switch e.Level {
+	case levelContainer:
+		allowed = levelSystem
+	case levelComponent:
+		allowed = levelContainer
+	}
+	if parentLevel == allowed {
+		return nil
+	}
+
+	if allowed == "" {
+		return fmt.Errorf("%s '%s' cannot be nested in a %s", structurizrKeywords[e.Level], e.ID, structurizrKeywords[parentLevel])
+	}
+	return fmt.Errorf("%s '%s' must be nested in a %s", structurizrKeywords[e.Level], e.ID, structurizrKeywords[allowed])
+}
+
+// viewIncludes returns the elements included in the view, either '*' or the
+// identifiers of the elements selected by the query that the view can show.
+func viewIncludes(view View, config *configuration.Config, levels map[string]string) (string, error) {
+	if view.Query.IsEmpty() {
+		return "*", nil
+	}
+
+	selected, err := query.ExecuteQuery(&view.Query, config)
+	if err != nil {
+		return "", err
+	}
+
+	var ids []string
+	for _, node := range selected.Nodes {
+		level, exists := levels[node.ID]
+		if exists && levelRanks[level] <= structurizrViewRanks[view.Type] && !slices.Contains(ids, structurizrID(node.ID)) {
+			ids = append(ids, structurizrID(node.ID))
+		}
+	}
+	if len(ids) == 0 {
+		return "", fmt.Errorf("the query does not select any elements the view can include")
+	}
+	sort.Strings(ids)
+
+	return strings.Join(ids, " "), nil
+}
+
+// formatArguments returns the quoted arguments of a DSL statement each preceded
+// by a space, trailing empty arguments are omitted.
+func formatArguments(args []string) string {
+	for len(args) > 0 && args[len(args)-1] == "" {
+		args = args[:len(args)-1]
+	}
+
+	var arguments strings.Builder
+	for _, arg := range args {
+		arguments.WriteString(fmt.Sprintf(" \"%s\"", diagram.ReplaceQuotes(arg)))
+	}
+	return arguments.String()
+}
+
+// structurizrID returns the node ID in a form Structurizr accepts as an identifier,
+// replacing the '/' in namespaced IDs such as 'payments/api' with '__' and any
+// other character that is not allowed with '_'.
+func structurizrID(id string) string {
+	id = strings.ReplaceAll(id, configuration.NamespaceSeparator, "__")
+	return invalidIdentifierRegex.ReplaceAllString(id, "_")
+}
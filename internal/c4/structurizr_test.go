@@ -0,0 +1,251 @@
+package c4
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+)
+
+func TestGenerateStructurizr(t *testing.T) {
+	err := filepath.Walk("../../tests", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			configPath := filepath.Join(path, "config.yaml")
+			structurizrConfigPath := filepath.Join(path, "structurizr.yaml")
+			structurizrPath := filepath.Join(path, "structurizr.dsl")
+
+			if _, err := os.Stat(configPath); os.IsNotExist(err) {
+				return nil
+			}
+			if _, err := os.Stat(structurizrConfigPath); os.IsNotExist(err) {
+				return nil
+			}
+			if _, err := os.Stat(structurizrPath); os.IsNotExist(err) {
+				return nil
+			}
+
+			relDir, err := filepath.Rel("../../tests", path)
+			if err != nil {
+				return err
+			}
+
+			sanitizedRelDir := strings.ReplaceAll(relDir, string(filepath.Separator), "#")
+
+			t.Run(sanitizedRelDir, func(t *testing.T) {
+				config, err := configuration.LoadConfig(configPath)
+				if err != nil {
+					t.Fatalf("Failed to load config: %v", err)
+				}
+
+				structurizrConfig, err := LoadStructurizr(structurizrConfigPath)
+				if err != nil {
+					t.Fatalf("Failed to load structurizr config: %v", err)
+				}
+
+				err = structurizrConfig.Validate()
+				if err != nil {
+					t.Fatalf("Structurizr config validation failed: %v", err)
+				}
+
+				expectedBytes, err := os.ReadFile(structurizrPath)
+				if err != nil {
+					t.Fatalf("Failed to read Structurizr file: %v", err)
+				}
+				expectedOutput := string(expectedBytes)
+
+				output, err := GenerateStructurizr(config, structurizrConfig)
+				if err != nil {
+					t.Fatalf("GenerateStructurizr returned error: %v", err)
+				}
+				if output != expectedOutput {
+					t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
+				}
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error walking through example folder: %v", err)
+	}
+}
+
+func TestGenerateStructurizrNesting(t *testing.T) {
+	config, err := configuration.ParseYAML(`
+nodes:
+  - id: platform
+    type: Platform
+  - id: api
+    type: Container
+    parent: platform
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	setting, err := ParseStructurizrYAML(`
+containerNodes:
+  filters:
+    - condition:
+        field: type
+        operator: equals
+        value: Container
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse settings: %v", err)
+	}
+
+	// The platform is not exported so the container is not nested in a software system
+	_, err = GenerateStructurizr(config, setting)
+	if err == nil {
+		t.Fatalf("Expected an error for a container outside of a software system")
+	}
+	expected := "container 'api' must be nested in a softwareSystem"
+	if err.Error() != expected {
+		t.Errorf("Expected error %q, got %q", expected, err.Error())
+	}
+}
+
+func TestGenerateStructurizrIDCollision(t *testing.T) {
+	config, err := configuration.ParseYAML(`
+nodes:
+  - id: shop.api
+    type: System
+  - id: shop_api
+    type: System
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	setting, err := ParseStructurizrYAML(`name: Shop`)
+	if err != nil {
+		t.Fatalf("Failed to parse settings: %v", err)
+	}
+
+	_, err = GenerateStructurizr(config, setting)
+	expected := "node IDs 'shop.api' and 'shop_api' are both rendered as 'shop_api'"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, got: %v", expected, err)
+	}
+}
+
+func TestLoadStructurizrQueryIn(t *testing.T) {
+	dir := t.TempDir()
+	queryYAML := `
+nodes:
+  filters:
+    - condition:
+        field: type
+        operator: equals
+        value: System
+`
+	if err := os.WriteFile(filepath.Join(dir, "query.yaml"), []byte(queryYAML), 0644); err != nil {
+		t.Fatalf("Failed to write query: %v", err)
+	}
+
+	settingsPath := filepath.Join(dir, "structurizr.yaml")
+	settingsYAML := `
+views:
+  - key: systems
+    type: systemLandscape
+    queryIn: query.yaml
+`
+	if err := os.WriteFile(settingsPath, []byte(settingsYAML), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+
+	setting, err := LoadStructurizr(settingsPath)
+	if err != nil {
+		t.Fatalf("LoadStructurizr returned error: %v", err)
+	}
+	if len(setting.Views[0].Query.Nodes.Filters) != 1 {
+		t.Errorf("Expected the view query to be loaded from the query file, got: %+v", setting.Views[0].Query)
+	}
+
+	// A view cannot set both an inline query and a query file
+	settingsYAML += `
+    query:
+      nodes:
+        filters:
+          - condition:
+              field: id
+              operator: equals
+              value: shop
+`
+	if err := os.WriteFile(settingsPath, []byte(settingsYAML), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+
+	_, err = LoadStructurizr(settingsPath)
+	expected := "view 'systems' cannot set both 'query' and 'queryIn'"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, got: %v", expected, err)
+	}
+}
+
+func TestLoadStructurizrQueryInConflict(t *testing.T) {
+	// A query without filters is still set and conflicts with the query file
+	setting, err := ParseStructurizrYAML(`
+views:
+  - key: systems
+    type: systemLandscape
+    queryIn: query.yaml
+    query:
+      boundary:
+        mode: stub
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse settings: %v", err)
+	}
+
+	err = setting.LoadQueries(t.TempDir())
+	expected := "view 'systems' cannot set both 'query' and 'queryIn'"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, got: %v", expected, err)
+	}
+}
+
+func TestGenerateStructurizrTagCommas(t *testing.T) {
+	config, err := configuration.ParseYAML(`
+nodes:
+  - id: shop
+    type: System
+    attributes:
+      owners:
+        - alice
+        - bob
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	setting, err := ParseStructurizrYAML(`
+tagAttributes:
+  - owners
+systemNodes:
+  filters:
+    - condition:
+        field: type
+        operator: equals
+        value: System
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse settings: %v", err)
+	}
+
+	output, err := GenerateStructurizr(config, setting)
+	if err != nil {
+		t.Fatalf("GenerateStructurizr returned error: %v", err)
+	}
+
+	// The comma of the list value would otherwise split it into two tags
+	expected := `shop = softwareSystem "shop" "" "System,owners:alice; bob"`
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+	}
+}
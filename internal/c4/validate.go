@@ -0,0 +1,147 @@
+package c4
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/common"
+	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
+)
+
+// Validate checks if the C4 settings are valid, returning all of the problems
+// found as common.ValidationErrors.
+func (c *C4) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the view is valid
+	if _, exists := viewRanks[c.View]; !exists {
+		errs.Add("setting", "view", fmt.Errorf("invalid view: %s", c.View))
+	}
+
+	// Validate the attributes used for the labels are valid, performing the same
+	// validation as attribute values
+	attributes := []struct {
+		field string
+		value string
+	}{
+		{"nodeLabel", c.NodeLabel},
+		{"nodeDescription", c.NodeDescription},
+		{"nodeTechnology", c.NodeTechnology},
+		{"linkTechnology", c.LinkTechnology},
+	}
+	for _, attribute := range attributes {
+		if attribute.value != "" {
+			errs.Add("setting", attribute.field, common.IsValidValue(attribute.value, attribute.field))
+		}
+	}
+
+	// Validate the scope is valid
+	errs.Merge("query", "scope", c.Scope.Validate())
+
+	// Validate the level queries are valid
+	errs = append(errs, c.Levels.validate()...)
+
+	// Validate the queries for the element variants are valid
+	errs.Merge("condition", "databaseNodes", c.DatabaseNodes.Validate())
+	errs.Merge("condition", "queueNodes", c.QueueNodes.Validate())
+	errs.Merge("condition", "externalNodes", c.ExternalNodes.Validate())
+
+	return errs.ErrOrNil()
+}
+
+// validate checks if the level queries are valid, at least one level is required.
+func (l *Levels) validate() common.ValidationErrors {
+	var errs common.ValidationErrors
+
+	levels := []struct {
+		field string
+		nodes query.Nodes
+	}{
+		{"personNodes", l.PersonNodes},
+		{"systemNodes", l.SystemNodes},
+		{"containerNodes", l.ContainerNodes},
+		{"componentNodes", l.ComponentNodes},
+	}
+	hasLevel := false
+	for _, level := range levels {
+		errs.Merge("condition", level.field, level.nodes.Validate())
+		hasLevel = hasLevel || len(level.nodes.Filters) > 0
+	}
+	if !hasLevel {
+		errs.Add("setting", "", fmt.Errorf("at least one of 'personNodes', 'systemNodes', 'containerNodes' or 'componentNodes' must be set"))
+	}
+
+	return errs
+}
+
+// Validate checks if the Structurizr settings are valid, returning all of the
+// problems found as common.ValidationErrors.
+func (s *Structurizr) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the attributes used for the elements and relationships are valid,
+	// performing the same validation as attribute values
+	attributes := []struct {
+		field string
+		value string
+	}{
+		{"nodeLabel", s.NodeLabel},
+		{"nodeDescription", s.NodeDescription},
+		{"nodeTechnology", s.NodeTechnology},
+		{"linkTechnology", s.LinkTechnology},
+	}
+	for _, attribute := range attributes {
+		if attribute.value != "" {
+			errs.Add("setting", attribute.field, common.IsValidValue(attribute.value, attribute.field))
+		}
+	}
+	for i, tagAttribute := range s.TagAttributes {
+		errs.Add("setting", fmt.Sprintf("tagAttributes[%d]", i), common.IsValidValue(tagAttribute, "tagAttributes"))
+	}
+
+	// Validate the level queries are valid
+	errs = append(errs, s.Levels.validate()...)
+
+	// Validate the views are valid and have unique keys
+	keys := make(map[string]bool)
+	for i := range s.Views {
+		if s.Views[i].Key != "" {
+			if keys[s.Views[i].Key] {
+				errs.Add("view", fmt.Sprintf("views[%d].key", i), fmt.Errorf("duplicate view key found: '%s'", s.Views[i].Key))
+			}
+			keys[s.Views[i].Key] = true
+		}
+		errs.Merge("view", fmt.Sprintf("views[%d]", i), s.Views[i].Validate())
+	}
+
+	return errs.ErrOrNil()
+}
+
+// autoLayoutDirections are the directions supported by the Structurizr automatic layout
+var autoLayoutDirections = []string{"tb", "bt", "lr", "rl"}
+
+func (v *View) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the key is valid
+	errs.Add("view", "key", common.IsValidName(v.Key, "key"))
+
+	// Validate the type is valid and the scope is set for the views that require one
+	if _, exists := structurizrViewRanks[v.Type]; !exists {
+		errs.Add("view", "type", fmt.Errorf("invalid view type: '%s'", v.Type))
+	} else if _, required := structurizrScopeLevels[v.Type]; required && v.Scope == "" {
+		errs.Add("view", "scope", fmt.Errorf("'scope' is required for '%s' views", v.Type))
+	} else if !required && v.Scope != "" {
+		errs.Add("view", "scope", fmt.Errorf("'scope' is not allowed for '%s' views", v.Type))
+	}
+
+	// Validate the query is valid
+	errs.Merge("query", "query", v.Query.Validate())
+
+	// Validate the auto layout direction is valid
+	if v.AutoLayout != "" && !slices.Contains(autoLayoutDirections, v.AutoLayout) {
+		errs.Add("view", "autoLayout", fmt.Errorf("invalid autoLayout: '%s'", v.AutoLayout))
+	}
+
+	return errs.ErrOrNil()
+}
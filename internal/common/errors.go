@@ -0,0 +1,110 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ValidationError describes a single problem found during validation.
+type ValidationError struct {
+	// The kind of element the problem was found in such as 'node' or 'condition'
+	Kind string `json:"kind"`
+	// The path to the element such as 'nodes[12].attributes.owner'
+	Path string `json:"path"`
+	// The description of the problem
+	Message string `json:"message"`
+	// The file the element was defined in, if known
+	File string `json:"file,omitempty"`
+	// The line the element was defined at, if known
+	Line int `json:"line,omitempty"`
+	// The column the element was defined at, if known
+	Column int `json:"column,omitempty"`
+}
+
+// Error returns the message of the validation error.
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// ValidationErrors collects all of the problems found during validation.
+type ValidationErrors []ValidationError
+
+// Error returns the messages of all of the validation errors, one per line.
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Message
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Add records a problem of the given kind at the given path.
+func (errs *ValidationErrors) Add(kind string, path string, err error) {
+	if err == nil {
+		return
+	}
+	*errs = append(*errs, ValidationError{Kind: kind, Path: path, Message: err.Error()})
+}
+
+// Merge records the problems from a nested validation with their paths
+// prefixed. Errors that are not ValidationErrors are recorded with the given
+// kind at the prefix path.
+func (errs *ValidationErrors) Merge(kind string, prefix string, err error) {
+	if err == nil {
+		return
+	}
+
+	var nested ValidationErrors
+	if !errors.As(err, &nested) {
+		errs.Add(kind, prefix, err)
+		return
+	}
+
+	for _, e := range nested {
+		e.Path = JoinPath(prefix, e.Path)
+		*errs = append(*errs, e)
+	}
+}
+
+// ErrOrNil returns the validation errors as an error, or nil if there are none.
+func (errs ValidationErrors) ErrOrNil() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// JSON returns the validation errors as a JSON array.
+func (errs ValidationErrors) JSON() string {
+	if errs == nil {
+		errs = ValidationErrors{}
+	}
+	data, err := json.MarshalIndent(errs, "", "  ")
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
+}
+
+// AsValidationErrors converts an error into ValidationErrors. Errors that are
+// not ValidationErrors are returned as a single problem of the given kind.
+func AsValidationErrors(kind string, err error) ValidationErrors {
+	var errs ValidationErrors
+	errs.Merge(kind, "", err)
+	return errs
+}
+
+// JoinPath joins two parts of a validation path such as 'nodes' and '[0].id'.
+func JoinPath(prefix string, path string) string {
+	switch {
+	case prefix == "":
+		return path
+	case path == "":
+		return prefix
+	case strings.HasPrefix(path, "["):
+		return prefix + path
+	default:
+		return prefix + "." + path
+	}
+}
@@ -0,0 +1,76 @@
+package common
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestJoinPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		path     string
+		expected string
+	}{
+		{"empty_prefix", "", "id", "id"},
+		{"empty_path", "nodes[0]", "", "nodes[0]"},
+		{"field", "nodes[0]", "id", "nodes[0].id"},
+		{"index", "nodes", "[0].id", "nodes[0].id"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := JoinPath(test.prefix, test.path)
+			if result != test.expected {
+				t.Errorf("JoinPath(%q, %q) = %q; want %q", test.prefix, test.path, result, test.expected)
+			}
+		})
+	}
+}
+
+func TestValidationErrorsMerge(t *testing.T) {
+	var nested ValidationErrors
+	nested.Add("condition", "condition", fmt.Errorf("first"))
+	nested.Add("condition", "", fmt.Errorf("second"))
+	nested.Add("condition", "ignored", nil)
+
+	var errs ValidationErrors
+	errs.Merge("condition", "filters[0]", nested)
+	errs.Merge("expand", "expand", fmt.Errorf("third"))
+	errs.Merge("expand", "expand", nil)
+
+	expected := ValidationErrors{
+		{Kind: "condition", Path: "filters[0].condition", Message: "first"},
+		{Kind: "condition", Path: "filters[0]", Message: "second"},
+		{Kind: "expand", Path: "expand", Message: "third"},
+	}
+
+	if len(errs) != len(expected) {
+		t.Fatalf("Merge produced %d errors; want %d", len(errs), len(expected))
+	}
+	for i := range expected {
+		if errs[i] != expected[i] {
+			t.Errorf("Merge error %d = %+v; want %+v", i, errs[i], expected[i])
+		}
+	}
+
+	if errs.Error() != "first\nsecond\nthird" {
+		t.Errorf("Error() = %q; want %q", errs.Error(), "first\nsecond\nthird")
+	}
+}
+
+func TestValidationErrorsErrOrNil(t *testing.T) {
+	var errs ValidationErrors
+	if errs.ErrOrNil() != nil {
+		t.Errorf("ErrOrNil() = %v; want nil", errs.ErrOrNil())
+	}
+
+	if errs.JSON() != "[]" {
+		t.Errorf("JSON() = %q; want %q", errs.JSON(), "[]")
+	}
+
+	errs.Add("node", "nodes[0].id", fmt.Errorf("invalid"))
+	if errs.ErrOrNil() == nil {
+		t.Errorf("ErrOrNil() = nil; want error")
+	}
+}
@@ -10,6 +10,9 @@ import (
 // Create a singleton validator instance
 var validate = validator.New()
 
+// numberRegex matches a non-negative integer or decimal number
+var numberRegex = regexp.MustCompile(`^\d+(\.\d+)?$`)
+
 func init() {
 	// Register the custom validator function
 	validate.RegisterValidation("pixel", isPixelValue)
@@ -49,3 +52,16 @@ func IsValidPixel(field string, value string) error {
 
 	return nil
 }
+
+// IsValidNumber checks the value is a non-negative number such as '2' or '1.5'
+func IsValidNumber(field string, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	if !numberRegex.MatchString(value) {
+		return fmt.Errorf("invalid number for '%s': '%s'", field, value)
+	}
+
+	return nil
+}
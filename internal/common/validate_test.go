@@ -57,3 +57,30 @@ func TestIsValidPixel(t *testing.T) {
 		})
 	}
 }
+
+func TestIsValidNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		field    string
+		value    string
+		expected error
+	}{
+		{"empty_number", "penwidth", "", nil},
+		{"valid_integer", "penwidth", "2", nil},
+		{"valid_decimal", "penwidth", "1.5", nil},
+		{"invalid_negative", "penwidth", "-1", fmt.Errorf("invalid number for 'penwidth': '-1'")},
+		{"invalid_suffix", "penwidth", "2px", fmt.Errorf("invalid number for 'penwidth': '2px'")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := IsValidNumber(test.field, test.value)
+			if err != nil && err.Error() != test.expected.Error() {
+				t.Errorf("IsValidNumber(%q, %q) = %v; want %v", test.field, test.value, err, test.expected)
+			}
+			if err == nil && test.expected != nil {
+				t.Errorf("IsValidNumber(%q, %q) = nil; want %v", test.field, test.value, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package configuration
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/common"
+)
+
+// LookupAttribute retrieves the value of an attribute by key. If the key is not
+// found directly and contains '.', it is treated as a path into nested maps.
+func LookupAttribute(attributes map[string]any, key string) (any, bool) {
+	if val, exists := attributes[key]; exists {
+		return val, true
+	}
+
+	if !strings.Contains(key, ".") {
+		return nil, false
+	}
+
+	// Walk the nested maps one segment at a time
+	var current any = attributes
+	for _, part := range strings.Split(key, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// NodeAttributeLabel returns the formatted value of the attribute for a node, or
+// an empty string if the key is not set or the node does not have the attribute.
+func NodeAttributeLabel(node Node, key string) string {
+	if key == "" {
+		return ""
+	}
+	val, exists := LookupAttribute(node.Attributes, key)
+	if !exists {
+		return ""
+	}
+	return FormatAttributeValue(val)
+}
+
+// FormatAttributeValue returns the string representation of an attribute value.
+// Lists are joined with ', ' and maps are written as sorted 'key: value' pairs.
+func FormatAttributeValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return formatTime(v)
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = FormatAttributeValue(item)
+		}
+		return strings.Join(parts, ", ")
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = fmt.Sprintf("%s: %s", k, FormatAttributeValue(v[k]))
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatTime returns the text of a timestamp the YAML decoder parsed from an
+// unquoted scalar, a date such as '2024-01-01' is written back as the date.
+func formatTime(t time.Time) string {
+	if t.Location() == time.UTC && t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		return t.Format(time.DateOnly)
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// validateAttributeValue checks that an attribute value is a supported type and
+// that nested lists and maps contain only valid keys and values.
+func validateAttributeValue(value any, field string) error {
+	switch v := value.(type) {
+	case nil:
+		return fmt.Errorf("'%s' cannot be empty", field)
+	case string:
+		return common.IsValidValue(v, field)
+	case bool, int, int64, uint64, float64, time.Time:
+		return nil
+	case []any:
+		if len(v) == 0 {
+			return fmt.Errorf("'%s' cannot be empty", field)
+		}
+		for _, item := range v {
+			err := validateAttributeValue(item, field)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]any:
+		if len(v) == 0 {
+			return fmt.Errorf("'%s' cannot be empty", field)
+		}
+		for key, item := range v {
+			err := common.IsValidName(key, "attribute.key")
+			if err != nil {
+				return err
+			}
+			err = validateAttributeValue(item, field+"."+key)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("'%s' has unsupported type %T", field, value)
+	}
+}
@@ -0,0 +1,125 @@
+package configuration
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatAttributeValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected string
+	}{
+		{"nil", nil, ""},
+		{"string", "Foo Service", "Foo Service"},
+		{"bool", true, "true"},
+		{"int", 3, "3"},
+		{"float", 1200.5, "1200.5"},
+		{"float_whole", 2.0, "2"},
+		{"date", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "2024-01-01"},
+		{"timestamp", time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC), "2024-01-01T10:30:00Z"},
+		{"list", []any{"alice", "bob"}, "alice, bob"},
+		{"map", map[string]any{"monthly": 100, "currency": "USD"}, "currency: USD, monthly: 100"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := FormatAttributeValue(test.value)
+			if actual != test.expected {
+				t.Errorf("FormatAttributeValue(%v) = %q; want %q", test.value, actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestLookupAttribute(t *testing.T) {
+	attributes := map[string]any{
+		"name":     "Foo Service",
+		"cost":     map[string]any{"monthly": 100},
+		"dot.name": "literal",
+	}
+
+	tests := []struct {
+		name     string
+		key      string
+		expected any
+		exists   bool
+	}{
+		{"top_level", "name", "Foo Service", true},
+		{"nested", "cost.monthly", 100, true},
+		{"literal_dot", "dot.name", "literal", true},
+		{"missing", "owner", nil, false},
+		{"missing_nested", "cost.yearly", nil, false},
+		{"through_scalar", "name.first", nil, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, exists := LookupAttribute(attributes, test.key)
+			if exists != test.exists || actual != test.expected {
+				t.Errorf("LookupAttribute(%q) = %v, %v; want %v, %v", test.key, actual, exists, test.expected, test.exists)
+			}
+		})
+	}
+}
+
+func TestNodeAttributeLabel(t *testing.T) {
+	node := Node{ID: "foo", Attributes: map[string]any{
+		"name": "Foo Service",
+		"cost": map[string]any{"monthly": 100},
+	}}
+
+	tests := []struct {
+		name     string
+		key      string
+		expected string
+	}{
+		{"direct", "name", "Foo Service"},
+		{"nested", "cost.monthly", "100"},
+		{"missing", "owner", ""},
+		{"empty_key", "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := NodeAttributeLabel(node, test.key)
+			if actual != test.expected {
+				t.Errorf("NodeAttributeLabel(%q) = %q; want %q", test.key, actual, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseYAMLDateAttributes(t *testing.T) {
+	config, err := ParseYAML(`
+nodes:
+  - id: api
+    type: Service
+    attributes:
+      created: 2024-01-01
+      audit:
+        last: 2024-06-30T12:00:00Z
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	// Unquoted dates are decoded as timestamps and are valid attribute values
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+
+	if label := NodeAttributeLabel(config.Nodes[0], "created"); label != "2024-01-01" {
+		t.Errorf("NodeAttributeLabel(created) = %q; want 2024-01-01", label)
+	}
+
+	// The dates are written back as they were written
+	output := config.YamlString()
+	for _, line := range []string{"created: 2024-01-01\n", "last: 2024-06-30T12:00:00Z\n"} {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, output)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package configuration
 
 import (
 	"fmt"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,6 +13,10 @@ type Node struct {
 	Type       string         `yaml:"type"`
 	Parent     string         `yaml:"parent,omitempty"`
 	Attributes map[string]any `yaml:"attributes,omitempty"`
+	// External marks a stub node that stands in for a node outside of a query result
+	External bool `yaml:"external,omitempty"`
+	// Origin records where the node was defined
+	Origin Origin `yaml:"-"`
 }
 
 // Link represents an interaction between nodes
@@ -21,20 +26,79 @@ type Link struct {
 	Target     string         `yaml:"target"`
 	Type       string         `yaml:"type"`
 	Attributes map[string]any `yaml:"attributes,omitempty"`
+	// Origin records where the link was defined
+	Origin Origin `yaml:"-"`
 }
 
 // Config holds the aggregated architecture
 type Config struct {
+	// Namespace qualifies the node IDs defined in this file such as 'payments'
+	Namespace string `yaml:"namespace,omitempty"`
+	// Imports lists the paths and glob patterns of other configuration files to merge in
+	Imports []string `yaml:"imports,omitempty"`
+	// Overlay modifies the nodes and links of the configurations this file is merged with
+	Overlay *Overlay `yaml:"overlay,omitempty"`
+	// Types declares the schemas for the node types and link types
+	Types *Types `yaml:"types,omitempty"`
 	Nodes []Node `yaml:"nodes"`
 	Links []Link `yaml:"links"`
+
+	// namespaced is set once the node IDs have been qualified with a namespace
+	namespaced bool
 }
 
 // YamlString returns the YAML representation of the configuration
 func (c *Config) YamlString() string {
+	// Write the timestamps in the attributes back as they were written
+	output := *c
+	output.Nodes = make([]Node, len(c.Nodes))
+	for i, node := range c.Nodes {
+		node.Attributes = yamlAttributes(node.Attributes)
+		output.Nodes[i] = node
+	}
+	output.Links = make([]Link, len(c.Links))
+	for i, link := range c.Links {
+		link.Attributes = yamlAttributes(link.Attributes)
+		output.Links[i] = link
+	}
+
 	// Marshall the config to a string
-	data, err := yaml.Marshal(c)
+	data, err := yaml.Marshal(&output)
 	if err != nil {
 		return fmt.Sprintf("error marshalling config: %v", err)
 	}
 	return string(data)
 }
+
+// yamlAttributes returns a copy of the attributes where the timestamps are
+// replaced with their text, as the YAML encoder would write a date such as
+// '2024-01-01' as a full timestamp.
+func yamlAttributes(attributes map[string]any) map[string]any {
+	if attributes == nil {
+		return nil
+	}
+	converted := make(map[string]any, len(attributes))
+	for key, value := range attributes {
+		converted[key] = yamlValue(value)
+	}
+	return converted
+}
+
+// yamlValue returns the value with the timestamps in it replaced with a plain
+// scalar of their text.
+func yamlValue(value any) any {
+	switch v := value.(type) {
+	case time.Time:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: formatTime(v)}
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = yamlValue(item)
+		}
+		return items
+	case map[string]any:
+		return yamlAttributes(v)
+	default:
+		return value
+	}
+}
@@ -0,0 +1,177 @@
+package configuration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ResolveImports loads the files listed in 'imports' and merges them into the
+// configuration ahead of its own nodes and links. Relative import paths and glob
+// patterns are resolved from the folder of filePath, or the working directory if
+// filePath is empty, absolute ones are used as they are, and the imports of
+// imported files are resolved recursively. Each file is
+// only loaded once and an import cycle is reported as an error. An overlay in the
+// configuration is applied to the result along with the attribute defaults declared
+// by the type schemas. The nodes and links of the configuration itself record
+// filePath as the file they were defined in.
+func (c *Config) ResolveImports(filePath string) error {
+	if filePath != "" {
+		c.setFile(filePath)
+	}
+
+	err := c.resolveImportsFrom(filePath, make(map[string]bool))
+	if err != nil {
+		return err
+	}
+
+	err = c.applyOwnOverlay()
+	if err != nil {
+		return err
+	}
+
+	// Set the default attribute values declared by the type schemas
+	c.applyDefaults()
+
+	return nil
+}
+
+// resolveImportsFrom resolves the imports of the configuration loaded from filePath
+// leaving an overlay in place if the configuration does not import anything. Files
+// in loaded are skipped and every file that is imported is added to it.
+func (c *Config) resolveImportsFrom(filePath string, loaded map[string]bool) error {
+	stack := []string{}
+	names := []string{}
+
+	if filePath != "" {
+		absPath, err := filepath.Abs(filePath)
+		if err != nil {
+			return fmt.Errorf("error resolving path '%s': %v", filePath, err)
+		}
+		stack = append(stack, absPath)
+		names = append(names, filePath)
+		loaded[absPath] = true
+	}
+
+	return c.resolveImports(filePath, stack, names, loaded)
+}
+
+// resolveImports resolves the imports of the configuration loaded from filePath where
+// stack holds the absolute paths of the files currently being imported, names holds the
+// same files as they were referenced for error messages, and loaded holds the absolute
+// paths of every file loaded so far.
+func (c *Config) resolveImports(filePath string, stack []string, names []string, loaded map[string]bool) error {
+	if len(c.Imports) == 0 {
+		return nil
+	}
+
+	configs := []*Config{}
+	for _, pattern := range c.Imports {
+		matches, err := importMatches(filePath, pattern)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range matches {
+			absPath, err := filepath.Abs(match)
+			if err != nil {
+				return fmt.Errorf("error resolving path '%s': %v", match, err)
+			}
+
+			// A file that is already being imported means the imports loop back on themselves
+			for i, stackPath := range stack {
+				if stackPath == absPath {
+					cycle := append(append([]string{}, names[i:]...), match)
+					return fmt.Errorf("import cycle detected: %s", strings.Join(cycle, " -> "))
+				}
+			}
+
+			// Files imported more than once are only merged the first time
+			if loaded[absPath] {
+				continue
+			}
+			loaded[absPath] = true
+
+			data, err := os.ReadFile(match)
+			if err != nil {
+				return fmt.Errorf("error reading import '%s': %v", match, err)
+			}
+
+			config, err := parseYAMLFile(string(data), match)
+			if err != nil {
+				return fmt.Errorf("error loading import '%s': %v", match, err)
+			}
+
+			err = config.resolveImports(match, append(stack, absPath), append(names, match), loaded)
+			if err != nil {
+				return err
+			}
+
+			configs = append(configs, config)
+		}
+	}
+
+	// Merge the imported configs ahead of this config's own nodes and links, applying
+	// this config's overlay on top of the configs it imports
+	own := &Config{Overlay: c.Overlay, Types: c.Types, Nodes: c.Nodes, Links: c.Links}
+	merged, err := MergeConfigs(append(configs, own)...)
+	if err != nil {
+		return err
+	}
+
+	*c = *merged
+	return nil
+}
+
+// importMatches returns the files matching an import pattern in the configuration
+// loaded from filePath. Relative patterns are resolved from the folder of filePath,
+// or the working directory if filePath is empty, while absolute patterns are used
+// as they are.
+func importMatches(filePath string, pattern string) ([]string, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("import in '%s' cannot be empty", displayName(filePath))
+	}
+
+	glob := pattern
+	if !filepath.IsAbs(pattern) {
+		baseDir := "."
+		if filePath != "" {
+			baseDir = filepath.Dir(filePath)
+		}
+		glob = filepath.Join(baseDir, pattern)
+	}
+
+	matches, err := filepath.Glob(glob)
+	if err != nil {
+		return nil, fmt.Errorf("invalid import pattern '%s' in '%s': %v", pattern, displayName(filePath), err)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("import '%s' in '%s' did not match any files", pattern, displayName(filePath))
+	}
+	return matches, nil
+}
+
+// displayName returns the file path for error messages, naming STDIN when it is empty.
+func displayName(filePath string) string {
+	if filePath == "" {
+		return "STDIN"
+	}
+	return filePath
+}
+
+// applyOwnOverlay applies an overlay that was not applied to any imports to the
+// configuration's own nodes and links.
+func (c *Config) applyOwnOverlay() error {
+	if c.Overlay == nil {
+		return nil
+	}
+
+	merged, err := MergeConfigs(c)
+	if err != nil {
+		return err
+	}
+
+	*c = *merged
+	return nil
+}
@@ -0,0 +1,139 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveImportsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		expected string
+	}{
+		{
+			"cycle",
+			map[string]string{
+				"a.yaml": "imports:\n  - b.yaml\nnodes: []\n",
+				"b.yaml": "imports:\n  - a.yaml\nnodes: []\n",
+			},
+			"import cycle detected: {dir}/a.yaml -> {dir}/b.yaml -> {dir}/a.yaml",
+		},
+		{
+			"self",
+			map[string]string{
+				"a.yaml": "imports:\n  - a.yaml\nnodes: []\n",
+			},
+			"import cycle detected: {dir}/a.yaml -> {dir}/a.yaml",
+		},
+		{
+			"missing",
+			map[string]string{
+				"a.yaml": "imports:\n  - missing.yaml\nnodes: []\n",
+			},
+			"import 'missing.yaml' in '{dir}/a.yaml' did not match any files",
+		},
+		{
+			"duplicate",
+			map[string]string{
+				"a.yaml": "imports:\n  - b.yaml\nnodes:\n  - id: node_a\n    type: Service\n",
+				"b.yaml": "nodes:\n  - id: node_a\n    type: Service\n",
+			},
+			"duplicate node ID 'node_a' found (at {dir}/a.yaml:4:5; first defined at {dir}/b.yaml:2:5)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range test.files {
+				err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+				if err != nil {
+					t.Fatalf("Failed to write %s: %v", name, err)
+				}
+			}
+
+			_, err := LoadConfig(filepath.Join(dir, "a.yaml"))
+			if err == nil {
+				t.Fatalf("LoadConfig() expected error, got none")
+			}
+
+			expected := strings.ReplaceAll(test.expected, "{dir}", dir)
+			if err.Error() != expected {
+				t.Errorf("LoadConfig() error = %q; want %q", err.Error(), expected)
+			}
+		})
+	}
+}
+
+func TestResolveImportsAbsolute(t *testing.T) {
+	dir := t.TempDir()
+	shared := filepath.Join(dir, "shared.yaml")
+	err := os.WriteFile(shared, []byte("nodes:\n  - id: shared\n    type: Service\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to write shared.yaml: %v", err)
+	}
+
+	config, err := ParseYAML("imports:\n  - " + shared + "\nnodes:\n  - id: own\n    type: Service\n")
+	if err != nil {
+		t.Fatalf("ParseYAML() returned error: %v", err)
+	}
+
+	// The importing file is in another folder so a relative pattern would not match
+	err = config.ResolveImports(filepath.Join(t.TempDir(), "main.yaml"))
+	if err != nil {
+		t.Fatalf("ResolveImports() returned error: %v", err)
+	}
+	if len(config.Nodes) != 2 || config.Nodes[0].ID != "shared" || config.Nodes[1].ID != "own" {
+		t.Errorf("ResolveImports() nodes = %v; want shared and own", config.Nodes)
+	}
+}
+
+func TestLoadFolderImportsSibling(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.yaml": "imports:\n  - c.yaml\nnodes:\n  - id: node_a\n    type: Service\n",
+		"b.yaml": "imports:\n  - c.yaml\nnodes:\n  - id: node_b\n    type: Service\n",
+		"c.yaml": "nodes:\n  - id: node_c\n    type: Service\n",
+	}
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config, err := LoadFolder(dir)
+	if err != nil {
+		t.Fatalf("LoadFolder() returned error: %v", err)
+	}
+
+	ids := []string{}
+	for _, node := range config.Nodes {
+		ids = append(ids, node.ID)
+	}
+	if strings.Join(ids, ",") != "node_c,node_a,node_b" {
+		t.Errorf("LoadFolder() nodes = %v; want [node_c node_a node_b]", ids)
+	}
+}
+
+func TestLoadFolderImportCycle(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.yaml": "imports:\n  - b.yaml\nnodes: []\n",
+		"b.yaml": "imports:\n  - a.yaml\nnodes: []\n",
+	}
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	_, err := LoadFolder(dir)
+	if err == nil || !strings.Contains(err.Error(), "import cycle detected") {
+		t.Errorf("LoadFolder() error = %v; want an import cycle", err)
+	}
+}
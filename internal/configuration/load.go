@@ -1,9 +1,13 @@
 package configuration
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/google/uuid"
 	"gopkg.in/yaml.v3"
@@ -11,6 +15,13 @@ import (
 
 // ParseYAML parses a YAML configuration string into a Config struct.
 func ParseYAML(config string) (*Config, error) {
+	return parseYAMLFile(config, "")
+}
+
+// parseYAMLFile parses a YAML configuration string read from the file, recording
+// the file in the origin of every node and link before the namespace is applied
+// so the errors qualifying the IDs name the file.
+func parseYAMLFile(config string, filePath string) (*Config, error) {
 	// Initialize an empty configuration
 	c := &Config{
 		Nodes: []Node{},
@@ -28,10 +39,44 @@ func ParseYAML(config string) (*Config, error) {
 		c.Links[i].ID = uuid.New().String()
 	}
 
+	// Record the file each node and link was loaded from
+	if filePath != "" {
+		c.setFile(filePath)
+	}
+
+	// Qualify the node IDs with the namespace declared in the file, the namespace is
+	// cleared so the qualified IDs are not qualified a second time if written back out
+	if c.Namespace != "" {
+		namespace := c.Namespace
+		c.Namespace = ""
+		err = c.applyNamespace(namespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return c, nil
 }
 
+// LoadOptions controls which files are loaded from a folder
+type LoadOptions struct {
+	// Glob patterns a file must match one of to be loaded, all files are loaded if empty
+	Include []string
+	// Glob patterns for files and folders that are not loaded
+	Exclude []string
+	// Use the path of the subfolder as the namespace for files that do not declare one
+	NamespaceFromFolder bool
+}
+
+// LoadFolder loads and merges all of the .yaml and .yml files in the folder and its subfolders.
 func LoadFolder(folderPath string) (*Config, error) {
+	return LoadFolderWithOptions(folderPath, LoadOptions{})
+}
+
+// LoadFolderWithOptions loads and merges the .yaml and .yml files in the folder and its
+// subfolders that match the include patterns and do not match the exclude patterns. Files
+// are loaded in lexical order of their path so the merged output is deterministic.
+func LoadFolderWithOptions(folderPath string, options LoadOptions) (*Config, error) {
 	// Verify the folderPath is a folder
 	fileInfo, err := os.Stat(folderPath)
 	if err != nil {
@@ -41,22 +86,104 @@ func LoadFolder(folderPath string) (*Config, error) {
 		return nil, fmt.Errorf("folderPath is not a directory")
 	}
 
-	// Loop through the folder contents loading in all .yaml files with LoadConfig
-	configs := []*Config{}
-	files, err := os.ReadDir(folderPath)
+	// Validate the patterns up front so a typo is not silently ignored
+	for _, pattern := range append(append([]string{}, options.Include...), options.Exclude...) {
+		if _, err := path.Match(strings.ReplaceAll(pattern, "**", "*"), ""); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern '%s': %v", pattern, err)
+		}
+	}
+
+	// Walk the folder parsing all of the matching files
+	type folderFile struct {
+		path   string
+		config *Config
+	}
+	files := []folderFile{}
+	err = filepath.WalkDir(folderPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(folderPath, filePath)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		if entry.IsDir() {
+			// Skip excluded folders entirely, but never the root folder
+			if relPath != "." && matchesAnyGlob(options.Exclude, relPath) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		ext := filepath.Ext(entry.Name())
+		if ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		if len(options.Include) > 0 && !matchesAnyGlob(options.Include, relPath) {
+			return nil
+		}
+		if matchesAnyGlob(options.Exclude, relPath) {
+			return nil
+		}
+
+		// Files in the root of the folder do not have a namespace derived for them
+		namespace := ""
+		if options.NamespaceFromFolder && path.Dir(relPath) != "." {
+			namespace = path.Dir(relPath)
+		}
+
+		config, err := parseConfigFile(filePath, namespace)
+		if err != nil {
+			return fmt.Errorf("error loading config file '%s': %v", filePath, err)
+		}
+		files = append(files, folderFile{path: filePath, config: config})
+		return nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error reading folder: %v", err)
 	}
+
+	// Find the files in the folder that are imported by another file in the folder
+	imported := make(map[string]bool)
 	for _, file := range files {
-		if file.IsDir() {
-			continue
+		for _, pattern := range file.config.Imports {
+			matches, err := importMatches(file.path, pattern)
+			if err != nil {
+				return nil, fmt.Errorf("error loading config file '%s': %v", file.path, err)
+			}
+			for _, match := range matches {
+				absPath, err := filepath.Abs(match)
+				if err != nil {
+					return nil, fmt.Errorf("error resolving path '%s': %v", match, err)
+				}
+				imported[absPath] = true
+			}
 		}
-		if filepath.Ext(file.Name()) == ".yaml" {
-			config, err := LoadConfig(filepath.Join(folderPath, file.Name()))
+	}
+
+	// Resolve the imports of the files that are not imported by another file first so
+	// each file is only merged once, then of any remaining files which can only be
+	// left over when the imports loop back on themselves
+	configs := []*Config{}
+	loaded := make(map[string]bool)
+	for _, pass := range []bool{false, true} {
+		for _, file := range files {
+			absPath, err := filepath.Abs(file.path)
 			if err != nil {
-				return nil, fmt.Errorf("error loading config file: %v", err)
+				return nil, fmt.Errorf("error resolving path '%s': %v", file.path, err)
 			}
-			configs = append(configs, config)
+			if loaded[absPath] || imported[absPath] != pass {
+				continue
+			}
+
+			err = file.config.resolveImportsFrom(file.path, loaded)
+			if err != nil {
+				return nil, fmt.Errorf("error loading config file '%s': %v", file.path, err)
+			}
+			configs = append(configs, file.config)
 		}
 	}
 
@@ -64,8 +191,91 @@ func LoadFolder(folderPath string) (*Config, error) {
 	return MergeConfigs(configs...)
 }
 
+// matchesAnyGlob checks if the slash separated path matches any of the patterns.
+func matchesAnyGlob(patterns []string, relPath string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob checks if the slash separated path matches the glob pattern. Patterns
+// without a '/' are matched against the file or folder name, otherwise the pattern
+// is matched against the whole path where '**' matches any number of folders.
+func matchGlob(pattern string, relPath string) bool {
+	if !strings.Contains(pattern, "/") {
+		matched, _ := path.Match(pattern, path.Base(relPath))
+		return matched
+	}
+	return matchGlobParts(strings.Split(pattern, "/"), strings.Split(relPath, "/"))
+}
+
+// matchGlobParts matches the pattern segments against the path segments.
+func matchGlobParts(patternParts []string, pathParts []string) bool {
+	if len(patternParts) == 0 {
+		return len(pathParts) == 0
+	}
+
+	if patternParts[0] == "**" {
+		// '**' matches zero or more folders
+		for i := 0; i <= len(pathParts); i++ {
+			if matchGlobParts(patternParts[1:], pathParts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(pathParts) == 0 {
+		return false
+	}
+
+	matched, _ := path.Match(patternParts[0], pathParts[0])
+	return matched && matchGlobParts(patternParts[1:], pathParts[1:])
+}
+
 // LoadConfig loads and parses a single YAML configuration file from the given path.
 func LoadConfig(filePath string) (*Config, error) {
+	config, err := loadConfigFile(filePath, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply an overlay that was not applied to any imports
+	err = config.applyOwnOverlay()
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the default attribute values declared by the type schemas
+	config.applyDefaults()
+
+	return config, nil
+}
+
+// loadConfigFile loads and parses a single YAML configuration file using the default
+// namespace if the file does not declare one. An overlay in the file is applied to the
+// files it imports, otherwise it is left in place to be applied when it is merged.
+func loadConfigFile(filePath string, defaultNamespace string) (*Config, error) {
+	config, err := parseConfigFile(filePath, defaultNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// Merge in the configuration files it imports
+	err = config.resolveImportsFrom(filePath, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// parseConfigFile parses a single YAML configuration file without resolving its
+// imports, using the default namespace if the file does not declare one.
+func parseConfigFile(filePath string, defaultNamespace string) (*Config, error) {
 
 	// Read the file contents to a string
 	data, err := os.ReadFile(filePath)
@@ -74,9 +284,23 @@ func LoadConfig(filePath string) (*Config, error) {
 	}
 
 	// Parse the YAML
-	return ParseYAML(string(data))
+	config, err := parseYAMLFile(string(data), filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !config.namespaced && defaultNamespace != "" {
+		err = config.applyNamespace(defaultNamespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
 }
 
+// MergeConfigs merges the configurations into one. Configurations with an overlay
+// are applied in order after all of the other configurations are merged.
 func MergeConfigs(configs ...*Config) (*Config, error) {
 	merged := &Config{
 		Nodes: []Node{},
@@ -84,11 +308,24 @@ func MergeConfigs(configs ...*Config) (*Config, error) {
 	}
 
 	nodeMap := make(map[string]Node)
+	overlays := []*Config{}
 	for _, config := range configs {
+		// Merge the type schemas
+		err := merged.mergeTypes(config.Types)
+		if err != nil {
+			return nil, err
+		}
+
+		// Overlays are applied once the base architecture is complete
+		if config.Overlay != nil {
+			overlays = append(overlays, config)
+			continue
+		}
+
 		// Merge nodes
 		for _, node := range config.Nodes {
-			if _, exists := nodeMap[node.ID]; exists {
-				return nil, fmt.Errorf("duplicate node ID '%s' found", node.ID)
+			if first, exists := nodeMap[node.ID]; exists {
+				return nil, errors.New(node.Origin.annotateDuplicate(fmt.Sprintf("duplicate node ID '%s' found", node.ID), first.Origin))
 			}
 			nodeMap[node.ID] = node
 			merged.Nodes = append(merged.Nodes, node)
@@ -98,5 +335,14 @@ func MergeConfigs(configs ...*Config) (*Config, error) {
 		merged.Links = append(merged.Links, config.Links...)
 	}
 
+	// Apply the overlays
+	err := merged.applyOverlays(overlays)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set the default attribute values declared by the type schemas
+	merged.applyDefaults()
+
 	return merged, nil
 }
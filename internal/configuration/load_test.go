@@ -0,0 +1,70 @@
+package configuration
+
+import (
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"name_match", "*.yml", "orders/links.yml", true},
+		{"name_no_match", "*.yml", "orders/services.yaml", false},
+		{"folder_name", "drafts", "payments/drafts", true},
+		{"path_match", "orders/*.yaml", "orders/services.yaml", true},
+		{"path_no_match", "orders/*.yaml", "payments/services.yaml", false},
+		{"double_star_zero", "**/services.yaml", "services.yaml", true},
+		{"double_star_many", "**/services.yaml", "payments/drafts/services.yaml", true},
+		{"double_star_suffix", "payments/**", "payments/drafts/fraud.yaml", true},
+		{"double_star_middle", "payments/**/fraud.yaml", "payments/drafts/fraud.yaml", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := matchGlob(test.pattern, test.path)
+			if result != test.expected {
+				t.Errorf("matchGlob(%q, %q) = %v; want %v", test.pattern, test.path, result, test.expected)
+			}
+		})
+	}
+}
+
+func TestLoadFolderWithOptions(t *testing.T) {
+	folder := "../../tests/example_nested_folders/configs"
+
+	tests := []struct {
+		name     string
+		options  LoadOptions
+		expected []string
+	}{
+		{"all", LoadOptions{}, []string{"order_service", "order_db", "fraud_service", "payment_service", "platform"}},
+		{"exclude_folder", LoadOptions{Exclude: []string{"drafts"}}, []string{"order_service", "order_db", "payment_service", "platform"}},
+		{"include_path", LoadOptions{Include: []string{"orders/**", "*.yml"}}, []string{"order_service", "order_db", "platform"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := LoadFolderWithOptions(folder, test.options)
+			if err != nil {
+				t.Fatalf("LoadFolderWithOptions() error = %v", err)
+			}
+
+			if len(config.Nodes) != len(test.expected) {
+				t.Fatalf("LoadFolderWithOptions() loaded %d nodes; want %d", len(config.Nodes), len(test.expected))
+			}
+			for i, id := range test.expected {
+				if config.Nodes[i].ID != id {
+					t.Errorf("node %d = %q; want %q", i, config.Nodes[i].ID, id)
+				}
+			}
+		})
+	}
+
+	_, err := LoadFolderWithOptions(folder, LoadOptions{Exclude: []string{"[drafts"}})
+	if err == nil {
+		t.Errorf("LoadFolderWithOptions() expected error for invalid pattern, got none")
+	}
+}
@@ -0,0 +1,96 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NamespaceSeparator separates the namespace from the local part of a node ID
+const NamespaceSeparator = "/"
+
+// applyNamespace qualifies the node IDs in the configuration with the namespace and
+// resolves the references in 'parent', 'source' and 'target' against it. References
+// without a '/' are local to the namespace, references that start with a '/' are
+// relative to the root such as '/platform', and any other reference containing a '/'
+// is already fully qualified such as 'orders/api'.
+func (c *Config) applyNamespace(namespace string) error {
+	err := validateNamespace(namespace)
+	if err != nil {
+		return err
+	}
+
+	for i := range c.Nodes {
+		node := &c.Nodes[i]
+		if strings.Contains(node.ID, NamespaceSeparator) {
+			return errors.New(node.Origin.annotate(fmt.Sprintf("node ID '%s' cannot contain '%s' in namespace '%s'", node.ID, NamespaceSeparator, namespace)))
+		}
+		node.ID = qualifyID(namespace, node.ID)
+		node.Parent = resolveReference(namespace, node.Parent)
+	}
+
+	for i := range c.Links {
+		c.Links[i].Source = resolveReference(namespace, c.Links[i].Source)
+		c.Links[i].Target = resolveReference(namespace, c.Links[i].Target)
+	}
+
+	// The nodes and links an overlay changes are references as well
+	if overlay := c.Overlay; overlay != nil {
+		for i := range overlay.Delete.Nodes {
+			overlay.Delete.Nodes[i] = resolveReference(namespace, overlay.Delete.Nodes[i])
+		}
+		for i := range overlay.Delete.Links {
+			overlay.Delete.Links[i].resolve(namespace)
+		}
+		for i := range overlay.Nodes {
+			overlay.Nodes[i].ID = resolveReference(namespace, overlay.Nodes[i].ID)
+			if parent := overlay.Nodes[i].Parent; parent != nil {
+				*parent = resolveReference(namespace, *parent)
+			}
+		}
+		for i := range overlay.Links {
+			overlay.Links[i].resolve(namespace)
+		}
+	}
+
+	c.namespaced = true
+	return nil
+}
+
+// validateNamespace checks the namespace is made up of non-empty parts separated by '/'.
+func validateNamespace(namespace string) error {
+	for _, part := range strings.Split(namespace, NamespaceSeparator) {
+		if part == "" {
+			return fmt.Errorf("invalid namespace: '%s'", namespace)
+		}
+	}
+	return nil
+}
+
+// qualifyID prefixes the local ID with the namespace.
+func qualifyID(namespace string, id string) string {
+	if namespace == "" || id == "" {
+		return id
+	}
+	return namespace + NamespaceSeparator + id
+}
+
+// resolveReference resolves a reference to a node ID from within the namespace.
+func resolveReference(namespace string, reference string) string {
+	switch {
+	case reference == "":
+		return reference
+	case strings.HasPrefix(reference, NamespaceSeparator):
+		return strings.TrimPrefix(reference, NamespaceSeparator)
+	case strings.Contains(reference, NamespaceSeparator):
+		return reference
+	default:
+		return qualifyID(namespace, reference)
+	}
+}
+
+// resolve resolves the source and target of the selector against the namespace.
+func (selector *LinkSelector) resolve(namespace string) {
+	selector.Source = resolveReference(namespace, selector.Source)
+	selector.Target = resolveReference(namespace, selector.Target)
+}
@@ -0,0 +1,114 @@
+package configuration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveReference(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		reference string
+		expected  string
+	}{
+		{"empty", "payments", "", ""},
+		{"local", "payments", "api", "payments/api"},
+		{"root", "payments", "/platform", "platform"},
+		{"qualified", "payments", "orders/api", "orders/api"},
+		{"root_qualified", "payments", "/orders/api", "orders/api"},
+		{"no_namespace", "", "api", "api"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := resolveReference(test.namespace, test.reference)
+			if result != test.expected {
+				t.Errorf("resolveReference(%q, %q) = %q; want %q", test.namespace, test.reference, result, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseYAMLNamespaceErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		yaml     string
+		expected string
+	}{
+		{"invalid_namespace", "namespace: payments//api\nnodes: []\n", "invalid namespace: 'payments//api'"},
+		{"qualified_id", "namespace: payments\nnodes:\n  - id: orders/api\n    type: Service\n", "node ID 'orders/api' cannot contain '/' in namespace 'payments' (at line 3, column 5)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseYAML(test.yaml)
+			if err == nil {
+				t.Fatalf("ParseYAML() expected error, got none")
+			}
+			if err.Error() != test.expected {
+				t.Errorf("ParseYAML() error = %q; want %q", err.Error(), test.expected)
+			}
+		})
+	}
+}
+
+func TestLoadConfigNamespaceErrorLocation(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "payments.yaml")
+	data := "namespace: payments\nnodes:\n  - id: orders/api\n    type: Service\n"
+	if err := os.WriteFile(filePath, []byte(data), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", filePath, err)
+	}
+
+	_, err := LoadConfig(filePath)
+	expected := fmt.Sprintf("node ID 'orders/api' cannot contain '/' in namespace 'payments' (at %s:3:5)", filePath)
+	if err == nil || err.Error() != expected {
+		t.Errorf("LoadConfig() error = %v; want %q", err, expected)
+	}
+}
+
+func TestLoadFolderNamespaceFromFolder(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"platform.yaml":          "nodes:\n  - id: platform\n    type: Region\n",
+		"payments/api.yaml":      "nodes:\n  - id: api\n    type: Service\n    parent: /platform\nlinks:\n  - source: api\n    target: ledger/api\n    type: gRPC\n",
+		"payments/ledger/a.yaml": "nodes:\n  - id: api\n    type: Service\n    parent: /payments/api\n",
+		"orders/declared.yaml":   "namespace: checkout\nnodes:\n  - id: api\n    type: Service\n",
+	}
+	for name, content := range files {
+		filePath := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatalf("Failed to create folder for %s: %v", name, err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	config, err := LoadFolderWithOptions(dir, LoadOptions{NamespaceFromFolder: true})
+	if err != nil {
+		t.Fatalf("LoadFolderWithOptions() error = %v", err)
+	}
+
+	expected := []string{"checkout/api", "payments/api", "payments/ledger/api", "platform"}
+	if len(config.Nodes) != len(expected) {
+		t.Fatalf("LoadFolderWithOptions() loaded %d nodes; want %d", len(config.Nodes), len(expected))
+	}
+	for i, id := range expected {
+		if config.Nodes[i].ID != id {
+			t.Errorf("node %d = %q; want %q", i, config.Nodes[i].ID, id)
+		}
+	}
+
+	if config.Nodes[2].Parent != "payments/api" {
+		t.Errorf("parent = %q; want %q", config.Nodes[2].Parent, "payments/api")
+	}
+
+	// Qualified references are not resolved against the namespace of the file
+	if config.Links[0].Target != "ledger/api" {
+		t.Errorf("link target = %q; want %q", config.Links[0].Target, "ledger/api")
+	}
+}
@@ -0,0 +1,85 @@
+package configuration
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Origin records where a node or link was defined
+type Origin struct {
+	// The path of the file, empty if the configuration was not loaded from a file
+	File string
+	// The line number starting at 1, 0 if unknown
+	Line int
+	// The column number starting at 1, 0 if unknown
+	Column int
+}
+
+// String returns the location as 'file:line:column', or 'line L, column C' if
+// the file is not known.
+func (origin Origin) String() string {
+	if origin.Line == 0 {
+		return origin.File
+	} else if origin.File == "" {
+		return fmt.Sprintf("line %d, column %d", origin.Line, origin.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", origin.File, origin.Line, origin.Column)
+}
+
+// annotate appends the location to the message if it is known.
+func (origin Origin) annotate(message string) string {
+	location := origin.String()
+	if location == "" {
+		return message
+	}
+	return fmt.Sprintf("%s (at %s)", message, location)
+}
+
+// annotateDuplicate appends the locations of both copies of a duplicated
+// element to the message if they are known.
+func (origin Origin) annotateDuplicate(message string, first Origin) string {
+	if first.String() == "" {
+		return origin.annotate(message)
+	} else if origin.String() == "" {
+		return fmt.Sprintf("%s (first defined at %s)", message, first)
+	}
+	return fmt.Sprintf("%s (at %s; first defined at %s)", message, origin, first)
+}
+
+// UnmarshalYAML decodes the node recording the line and column it was defined at.
+func (node *Node) UnmarshalYAML(value *yaml.Node) error {
+	type plain Node
+	err := value.Decode((*plain)(node))
+	if err != nil {
+		return err
+	}
+	node.Origin = Origin{Line: value.Line, Column: value.Column}
+	return nil
+}
+
+// UnmarshalYAML decodes the link recording the line and column it was defined at.
+func (rel *Link) UnmarshalYAML(value *yaml.Node) error {
+	type plain Link
+	err := value.Decode((*plain)(rel))
+	if err != nil {
+		return err
+	}
+	rel.Origin = Origin{Line: value.Line, Column: value.Column}
+	return nil
+}
+
+// setFile records the cleaned file path in the origin of every node and link.
+func (c *Config) setFile(filePath string) {
+	filePath = filepath.Clean(filePath)
+	for i := range c.Nodes {
+		c.Nodes[i].Origin.File = filePath
+	}
+	for i := range c.Links {
+		c.Links[i].Origin.File = filePath
+	}
+	if c.Overlay != nil {
+		c.Overlay.Origin.File = filePath
+	}
+}
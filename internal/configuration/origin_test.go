@@ -0,0 +1,57 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseYAMLOrigin(t *testing.T) {
+	config, err := ParseYAML("nodes:\n  - id: node_a\n    type: Service\n  - id: node_b\n    type: Service\nlinks:\n  - source: node_a\n    target: node_b\n    type: REST\n")
+	if err != nil {
+		t.Fatalf("ParseYAML() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		origin   Origin
+		expected string
+	}{
+		{"first_node", config.Nodes[0].Origin, "line 2, column 5"},
+		{"second_node", config.Nodes[1].Origin, "line 4, column 5"},
+		{"link", config.Links[0].Origin, "line 7, column 5"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.origin.String() != test.expected {
+				t.Errorf("Origin = %q; want %q", test.origin.String(), test.expected)
+			}
+		})
+	}
+}
+
+func TestLoadFolderDuplicateOrigin(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"a.yaml": "nodes:\n  - id: node_a\n    type: Service\n",
+		"b.yaml": "nodes:\n  - id: node_b\n    type: Service\n  - id: node_a\n    type: Database\n",
+	}
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	_, err := LoadFolder(dir)
+	if err == nil {
+		t.Fatalf("LoadFolder() expected duplicate node error, got none")
+	}
+
+	expected := "duplicate node ID 'node_a' found (at " + filepath.Join(dir, "b.yaml") + ":4:5; first defined at " + filepath.Join(dir, "a.yaml") + ":2:5)"
+	if err.Error() != expected {
+		t.Errorf("LoadFolder() error = %q; want %q", err.Error(), expected)
+	}
+}
@@ -0,0 +1,231 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Overlay modifies the nodes and links of a base architecture when configurations
+// are merged. The nodes and links defined alongside an overlay are added after it
+// is applied.
+type Overlay struct {
+	// Order in which overlays are applied, lower values first, ties keep the load order
+	Order int `yaml:"order,omitempty"`
+	// Delete lists the nodes and links that are removed
+	Delete OverlayDelete `yaml:"delete,omitempty"`
+	// Nodes lists the changes to existing nodes
+	Nodes []NodePatch `yaml:"nodes,omitempty"`
+	// Links lists the changes to existing links
+	Links []LinkPatch `yaml:"links,omitempty"`
+	// Origin records where the overlay was defined
+	Origin Origin `yaml:"-"`
+}
+
+// OverlayDelete lists the nodes and links an overlay removes
+type OverlayDelete struct {
+	// The IDs of the nodes to remove along with the links to and from them
+	Nodes []string `yaml:"nodes,omitempty"`
+	// The links to remove
+	Links []LinkSelector `yaml:"links,omitempty"`
+}
+
+// LinkSelector selects the links between two nodes, optionally only of one type
+type LinkSelector struct {
+	Source string `yaml:"source"`
+	Target string `yaml:"target"`
+	Type   string `yaml:"type,omitempty"`
+}
+
+// NodePatch changes an existing node, only the properties that are set are changed
+type NodePatch struct {
+	ID   string `yaml:"id"`
+	Type string `yaml:"type,omitempty"`
+	// Parent is the new parent of the node, an empty string moves it to the top level
+	Parent *string `yaml:"parent,omitempty"`
+	// Attributes are added or overridden, an attribute set to null is removed
+	Attributes map[string]any `yaml:"attributes,omitempty"`
+}
+
+// LinkPatch changes the attributes of the existing links it selects
+type LinkPatch struct {
+	LinkSelector `yaml:",inline"`
+	// Attributes are added or overridden, an attribute set to null is removed
+	Attributes map[string]any `yaml:"attributes,omitempty"`
+}
+
+// UnmarshalYAML decodes the overlay recording the line and column it was defined at.
+func (overlay *Overlay) UnmarshalYAML(value *yaml.Node) error {
+	type plain Overlay
+	err := value.Decode((*plain)(overlay))
+	if err != nil {
+		return err
+	}
+	overlay.Origin = Origin{Line: value.Line, Column: value.Column}
+	return nil
+}
+
+// matches checks if the link is selected.
+func (selector LinkSelector) matches(link Link) bool {
+	return link.Source == selector.Source && link.Target == selector.Target &&
+		(selector.Type == "" || link.Type == selector.Type)
+}
+
+// String returns the selector as 'source -> target' including the type if set.
+func (selector LinkSelector) String() string {
+	if selector.Type == "" {
+		return fmt.Sprintf("%s -> %s", selector.Source, selector.Target)
+	}
+	return fmt.Sprintf("%s -> %s (%s)", selector.Source, selector.Target, selector.Type)
+}
+
+// applyOverlays applies the overlays to the merged configuration in order.
+func (merged *Config) applyOverlays(overlays []*Config) error {
+	sort.SliceStable(overlays, func(i, j int) bool {
+		return overlays[i].Overlay.Order < overlays[j].Overlay.Order
+	})
+
+	for _, config := range overlays {
+		err := merged.applyOverlay(config)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyOverlay removes, changes and then adds the nodes and links of the overlay
+// configuration. A change that does not match any node or link is an error so a
+// stale overlay does not go unnoticed, as is a deleted node that leaves children
+// behind.
+func (merged *Config) applyOverlay(config *Config) error {
+	overlay := config.Overlay
+
+	// Remove the nodes along with the links to and from them
+	for _, id := range overlay.Delete.Nodes {
+		index := merged.nodeIndex(id)
+		if index < 0 {
+			return errors.New(overlay.Origin.annotate(fmt.Sprintf("overlay cannot delete non-existent node '%s'", id)))
+		}
+		merged.Nodes = append(merged.Nodes[:index], merged.Nodes[index+1:]...)
+
+		links := []Link{}
+		for _, link := range merged.Links {
+			if link.Source != id && link.Target != id {
+				links = append(links, link)
+			}
+		}
+		merged.Links = links
+	}
+
+	// Remove the links
+	for _, selector := range overlay.Delete.Links {
+		links := []Link{}
+		for _, link := range merged.Links {
+			if !selector.matches(link) {
+				links = append(links, link)
+			}
+		}
+		if len(links) == len(merged.Links) {
+			return errors.New(overlay.Origin.annotate(fmt.Sprintf("overlay cannot delete non-existent link '%s'", selector)))
+		}
+		merged.Links = links
+	}
+
+	// Change the nodes
+	for _, patch := range overlay.Nodes {
+		index := merged.nodeIndex(patch.ID)
+		if index < 0 {
+			return errors.New(overlay.Origin.annotate(fmt.Sprintf("overlay cannot change non-existent node '%s'", patch.ID)))
+		}
+
+		node := &merged.Nodes[index]
+		if patch.Type != "" {
+			node.Type = patch.Type
+		}
+		if patch.Parent != nil {
+			node.Parent = *patch.Parent
+		}
+		node.Attributes = patchAttributes(node.Attributes, patch.Attributes)
+	}
+
+	// Change the links
+	for _, patch := range overlay.Links {
+		matched := false
+		for i := range merged.Links {
+			if patch.matches(merged.Links[i]) {
+				merged.Links[i].Attributes = patchAttributes(merged.Links[i].Attributes, patch.Attributes)
+				matched = true
+			}
+		}
+		if !matched {
+			return errors.New(overlay.Origin.annotate(fmt.Sprintf("overlay cannot change non-existent link '%s'", patch.LinkSelector)))
+		}
+	}
+
+	// Add the nodes and links defined alongside the overlay
+	for _, node := range config.Nodes {
+		if index := merged.nodeIndex(node.ID); index >= 0 {
+			return errors.New(node.Origin.annotateDuplicate(fmt.Sprintf("duplicate node ID '%s' found", node.ID), merged.Nodes[index].Origin))
+		}
+		merged.Nodes = append(merged.Nodes, node)
+	}
+	merged.Links = append(merged.Links, config.Links...)
+
+	// The children of a deleted node must be deleted or given a new parent as well
+	for _, id := range overlay.Delete.Nodes {
+		if merged.nodeIndex(id) >= 0 {
+			continue
+		}
+		children := []string{}
+		for _, node := range merged.Nodes {
+			if node.Parent == id {
+				children = append(children, fmt.Sprintf("'%s'", node.ID))
+			}
+		}
+		if len(children) > 0 {
+			return errors.New(overlay.Origin.annotate(fmt.Sprintf("overlay deletes node '%s' but not its children %s which must be deleted or given a new parent", id, strings.Join(children, ", "))))
+		}
+	}
+
+	return nil
+}
+
+// nodeIndex returns the index of the node with the ID or -1 if it does not exist.
+func (c *Config) nodeIndex(id string) int {
+	for i, node := range c.Nodes {
+		if node.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// patchAttributes returns a copy of the attributes with the changes applied where
+// a change set to null removes the attribute.
+func patchAttributes(attributes map[string]any, changes map[string]any) map[string]any {
+	if len(changes) == 0 {
+		return attributes
+	}
+
+	result := make(map[string]any, len(attributes)+len(changes))
+	for key, value := range attributes {
+		result[key] = value
+	}
+	for key, value := range changes {
+		if value == nil {
+			delete(result, key)
+		} else {
+			result[key] = value
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
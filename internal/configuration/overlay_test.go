@@ -0,0 +1,147 @@
+package configuration
+
+import (
+	"testing"
+)
+
+// parseConfigs parses each YAML string recording its index as the file name.
+func parseConfigs(t *testing.T, contents ...string) []*Config {
+	configs := []*Config{}
+	for i, content := range contents {
+		config, err := ParseYAML(content)
+		if err != nil {
+			t.Fatalf("ParseYAML() error = %v", err)
+		}
+		config.setFile(string(rune('a'+i)) + ".yaml")
+		configs = append(configs, config)
+	}
+	return configs
+}
+
+func TestMergeConfigsOverlayOrder(t *testing.T) {
+	base := "nodes:\n  - id: api\n    type: Service\n    attributes:\n      env: base\n"
+	second := "overlay:\n  order: 2\n  nodes:\n    - id: api\n      attributes:\n        env: second\n"
+	first := "overlay:\n  order: 1\n  nodes:\n    - id: api\n      type: Function\n      attributes:\n        env: first\n"
+
+	// The overlays are listed before the base and out of order
+	merged, err := MergeConfigs(parseConfigs(t, second, first, base)...)
+	if err != nil {
+		t.Fatalf("MergeConfigs() error = %v", err)
+	}
+
+	if merged.Overlay != nil {
+		t.Errorf("MergeConfigs() left the overlay in the merged config")
+	}
+	if merged.Nodes[0].Type != "Function" {
+		t.Errorf("type = %q; want %q", merged.Nodes[0].Type, "Function")
+	}
+	if merged.Nodes[0].Attributes["env"] != "second" {
+		t.Errorf("env = %v; want %q", merged.Nodes[0].Attributes["env"], "second")
+	}
+}
+
+func TestMergeConfigsOverlayDeleteLinks(t *testing.T) {
+	base := "nodes:\n  - id: a\n    type: Service\n  - id: b\n    type: Service\nlinks:\n  - source: a\n    target: b\n    type: REST\n  - source: a\n    target: b\n    type: gRPC\n"
+	overlay := "overlay:\n  delete:\n    links:\n      - source: a\n        target: b\n        type: REST\n"
+
+	merged, err := MergeConfigs(parseConfigs(t, base, overlay)...)
+	if err != nil {
+		t.Fatalf("MergeConfigs() error = %v", err)
+	}
+
+	if len(merged.Links) != 1 || merged.Links[0].Type != "gRPC" {
+		t.Errorf("links = %+v; want only the gRPC link", merged.Links)
+	}
+}
+
+func TestMergeConfigsOverlayErrors(t *testing.T) {
+	base := "nodes:\n  - id: a\n    type: Service\n  - id: b\n    type: Service\nlinks:\n  - source: a\n    target: b\n    type: REST\n"
+
+	tests := []struct {
+		name     string
+		overlay  string
+		expected string
+	}{
+		{"delete_node", "overlay:\n  delete:\n    nodes:\n      - c\n", "overlay cannot delete non-existent node 'c' (at b.yaml:2:3)"},
+		{"delete_link", "overlay:\n  delete:\n    links:\n      - source: b\n        target: a\n", "overlay cannot delete non-existent link 'b -> a' (at b.yaml:2:3)"},
+		{"change_node", "overlay:\n  nodes:\n    - id: c\n      type: Service\n", "overlay cannot change non-existent node 'c' (at b.yaml:2:3)"},
+		{"change_link", "overlay:\n  links:\n    - source: a\n      target: b\n      type: gRPC\n", "overlay cannot change non-existent link 'a -> b (gRPC)' (at b.yaml:2:3)"},
+		{"duplicate_node", "overlay: {}\nnodes:\n  - id: a\n    type: Service\n", "duplicate node ID 'a' found (at b.yaml:3:5; first defined at a.yaml:2:5)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := MergeConfigs(parseConfigs(t, base, test.overlay)...)
+			if err == nil {
+				t.Fatalf("MergeConfigs() expected error, got none")
+			}
+			if err.Error() != test.expected {
+				t.Errorf("MergeConfigs() error = %q; want %q", err.Error(), test.expected)
+			}
+		})
+	}
+}
+
+func TestMergeConfigsOverlayParent(t *testing.T) {
+	base := "nodes:\n  - id: vpc\n    type: Network\n  - id: api\n    type: Service\n    parent: vpc\n  - id: db\n    type: Database\n    parent: vpc\n"
+
+	tests := []struct {
+		name     string
+		overlay  string
+		parents  map[string]string
+		expected string
+	}{
+		{
+			"clear_parent",
+			"overlay:\n  nodes:\n    - id: api\n      parent: \"\"\n",
+			map[string]string{"api": "", "db": "vpc", "vpc": ""},
+			"",
+		},
+		{
+			"delete_parent_with_children",
+			"overlay:\n  delete:\n    nodes:\n      - vpc\n",
+			nil,
+			"overlay deletes node 'vpc' but not its children 'api', 'db' which must be deleted or given a new parent (at b.yaml:2:3)",
+		},
+		{
+			"delete_parent_and_children",
+			"overlay:\n  delete:\n    nodes:\n      - db\n      - vpc\n  nodes:\n    - id: api\n      parent: \"\"\n",
+			map[string]string{"api": ""},
+			"",
+		},
+		{
+			"replace_parent",
+			"overlay:\n  delete:\n    nodes:\n      - vpc\nnodes:\n  - id: vpc\n    type: Network\n",
+			map[string]string{"api": "vpc", "db": "vpc", "vpc": ""},
+			"",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, err := MergeConfigs(parseConfigs(t, base, test.overlay)...)
+			if test.expected != "" {
+				if err == nil || err.Error() != test.expected {
+					t.Fatalf("MergeConfigs() error = %v; want %q", err, test.expected)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MergeConfigs() error = %v", err)
+			}
+
+			parents := make(map[string]string)
+			for _, node := range merged.Nodes {
+				parents[node.ID] = node.Parent
+			}
+			if len(parents) != len(test.parents) {
+				t.Fatalf("nodes = %v; want %v", parents, test.parents)
+			}
+			for id, parent := range test.parents {
+				if parents[id] != parent {
+					t.Errorf("parent of %s = %q; want %q", id, parents[id], parent)
+				}
+			}
+		})
+	}
+}
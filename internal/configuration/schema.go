@@ -0,0 +1,420 @@
+package configuration
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/common"
+)
+
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+	AttributeTypeList    = "list"
+	AttributeTypeMap     = "map"
+)
+
+// Types declares the schemas for node types and link types
+type Types struct {
+	// Nodes maps a node type to its schema
+	Nodes map[string]TypeSchema `yaml:"nodes,omitempty"`
+	// Links maps a link type to its schema
+	Links map[string]TypeSchema `yaml:"links,omitempty"`
+}
+
+// TypeSchema declares the attributes of a node type or link type
+type TypeSchema struct {
+	// Attributes maps an attribute key to its schema
+	Attributes map[string]AttributeSchema `yaml:"attributes,omitempty"`
+	// Parents lists the node types the parent of a node of this type must be one of, only for node types
+	Parents []string `yaml:"parents,omitempty"`
+	// Connects lists the node types a link of this type can connect, only for link types
+	Connects []Connection `yaml:"connects,omitempty"`
+}
+
+// Connection is a pair of source and target node types, an empty type matches any node type
+type Connection struct {
+	Source string `yaml:"source,omitempty"`
+	Target string `yaml:"target,omitempty"`
+}
+
+// AttributeSchema declares the rules for an attribute
+type AttributeSchema struct {
+	// Required attributes must be set unless they have a default
+	Required bool `yaml:"required,omitempty"`
+	// Type of the value, one of 'string', 'number', 'boolean', 'list' or 'map'
+	Type string `yaml:"type,omitempty"`
+	// Values lists the allowed values, each item of a list must be allowed
+	Values []string `yaml:"values,omitempty"`
+	// Pattern is a regular expression the value must match, each item of a list must match
+	Pattern string `yaml:"pattern,omitempty"`
+	// Default is the value used when the attribute is not set
+	Default any `yaml:"default,omitempty"`
+
+	// pattern is the compiled Pattern, set once the schemas are compiled
+	pattern *regexp.Regexp
+}
+
+// mergeTypes merges the type schemas into the config, declaring the same type
+// in more than one file is an error.
+func (c *Config) mergeTypes(types *Types) error {
+	if types == nil {
+		return nil
+	}
+	if c.Types == nil {
+		c.Types = &Types{}
+	}
+
+	var err error
+	c.Types.Nodes, err = mergeTypeSchemas(c.Types.Nodes, types.Nodes, "node")
+	if err != nil {
+		return err
+	}
+	c.Types.Links, err = mergeTypeSchemas(c.Types.Links, types.Links, "link")
+	return err
+}
+
+// mergeTypeSchemas adds the schemas to the existing schemas.
+func mergeTypeSchemas(existing map[string]TypeSchema, schemas map[string]TypeSchema, kind string) (map[string]TypeSchema, error) {
+	if len(schemas) == 0 {
+		return existing, nil
+	}
+	if existing == nil {
+		existing = make(map[string]TypeSchema)
+	}
+	for name, schema := range schemas {
+		if _, exists := existing[name]; exists {
+			return nil, fmt.Errorf("duplicate schema for %s type '%s' found", kind, name)
+		}
+		existing[name] = schema
+	}
+	return existing, nil
+}
+
+// compilePatterns compiles the pattern of every attribute schema once so it is not
+// compiled again for every value it is checked against. Invalid patterns are left
+// uncompiled to be reported when the schemas are validated.
+func (types *Types) compilePatterns() {
+	for _, schemas := range []map[string]TypeSchema{types.Nodes, types.Links} {
+		for _, schema := range schemas {
+			for key, attribute := range schema.Attributes {
+				if attribute.Pattern == "" || attribute.pattern != nil {
+					continue
+				}
+				if re, err := regexp.Compile(attribute.Pattern); err == nil {
+					attribute.pattern = re
+					schema.Attributes[key] = attribute
+				}
+			}
+		}
+	}
+}
+
+// applyDefaults sets the default value of every attribute that is not set on the
+// nodes and links with a type schema.
+func (c *Config) applyDefaults() {
+	if c.Types == nil {
+		return
+	}
+	c.Types.compilePatterns()
+
+	for i := range c.Nodes {
+		if schema, exists := c.Types.Nodes[c.Nodes[i].Type]; exists {
+			c.Nodes[i].Attributes = schema.applyDefaults(c.Nodes[i].Attributes)
+		}
+	}
+	for i := range c.Links {
+		if schema, exists := c.Types.Links[c.Links[i].Type]; exists {
+			c.Links[i].Attributes = schema.applyDefaults(c.Links[i].Attributes)
+		}
+	}
+}
+
+// applyDefaults returns the attributes with the default values added.
+func (schema TypeSchema) applyDefaults(attributes map[string]any) map[string]any {
+	for _, key := range sortedKeys(schema.Attributes) {
+		attribute := schema.Attributes[key]
+
+		// Invalid defaults are reported when the schema is validated and are not applied
+		if attribute.Default == nil || attribute.check(key, attribute.Default, "") != nil {
+			continue
+		}
+		if _, exists := LookupAttribute(attributes, key); exists {
+			continue
+		}
+		attributes = setAttribute(attributes, key, attribute.Default)
+	}
+	return attributes
+}
+
+// setAttribute returns the attributes with the value set at the key, where a key
+// containing '.' is a path into nested maps that are created as needed. The value
+// is not set if the path runs into an attribute that is not a map.
+func setAttribute(attributes map[string]any, key string, value any) map[string]any {
+	if attributes == nil {
+		attributes = make(map[string]any)
+	}
+
+	parts := strings.Split(key, ".")
+	current := attributes
+	for _, part := range parts[:len(parts)-1] {
+		next, exists := current[part]
+		if !exists {
+			nested := make(map[string]any)
+			current[part] = nested
+			current = nested
+			continue
+		}
+		nested, ok := next.(map[string]any)
+		if !ok {
+			return attributes
+		}
+		current = nested
+	}
+	current[parts[len(parts)-1]] = value
+
+	return attributes
+}
+
+// validate returns the problems with the type schemas with paths relative to 'types'.
+func (types *Types) validate() common.ValidationErrors {
+	var errs common.ValidationErrors
+
+	types.compilePatterns()
+
+	for _, name := range sortedKeys(types.Nodes) {
+		errs.Merge("schema", "nodes."+name, types.Nodes[name].validate("node").ErrOrNil())
+	}
+	for _, name := range sortedKeys(types.Links) {
+		errs.Merge("schema", "links."+name, types.Links[name].validate("link").ErrOrNil())
+	}
+
+	return errs
+}
+
+// validate returns the problems with the type schema for a node or link type.
+func (schema TypeSchema) validate(kind string) common.ValidationErrors {
+	var errs common.ValidationErrors
+
+	if kind != "node" && len(schema.Parents) > 0 {
+		errs.Add("schema", "parents", fmt.Errorf("'parents' property is only allowed for node types"))
+	}
+	for i, parent := range schema.Parents {
+		errs.Add("schema", fmt.Sprintf("parents[%d]", i), common.IsValidName(parent, "parents"))
+	}
+
+	if kind != "link" && len(schema.Connects) > 0 {
+		errs.Add("schema", "connects", fmt.Errorf("'connects' property is only allowed for link types"))
+	}
+
+	for _, key := range sortedKeys(schema.Attributes) {
+		path := common.JoinPath("attributes", key)
+		attribute := schema.Attributes[key]
+
+		err := common.IsValidName(key, "attribute.key")
+		if err != nil {
+			errs.Add("schema", path, err)
+			continue
+		}
+
+		switch attribute.Type {
+		case "":
+		case AttributeTypeString:
+		case AttributeTypeNumber:
+		case AttributeTypeBoolean:
+		case AttributeTypeList:
+		case AttributeTypeMap:
+		default:
+			errs.Add("schema", path+".type", fmt.Errorf("invalid attribute type '%s' for attribute '%s'", attribute.Type, key))
+			continue
+		}
+
+		// Patterns that could not be compiled are invalid
+		if attribute.Pattern != "" && attribute.pattern == nil {
+			_, err := regexp.Compile(attribute.Pattern)
+			errs.Add("schema", path+".pattern", fmt.Errorf("invalid pattern '%s' for attribute '%s': %v", attribute.Pattern, key, err))
+			continue
+		}
+
+		// The default must follow the rules it is a default for
+		if attribute.Default != nil {
+			errs.Add("schema", path+".default", attribute.check(key, attribute.Default, " for the default"))
+		}
+	}
+
+	return errs
+}
+
+// validateAttributes returns the problems with the attributes for the schema of the
+// element's type with paths relative to the element.
+func (schema TypeSchema) validateAttributes(attributes map[string]any, kind string, typeName string) common.ValidationErrors {
+	var errs common.ValidationErrors
+
+	for _, key := range sortedKeys(schema.Attributes) {
+		path := common.JoinPath("attributes", key)
+		attribute := schema.Attributes[key]
+
+		value, exists := LookupAttribute(attributes, key)
+		if !exists {
+			if attribute.Required && attribute.Default == nil {
+				errs.Add(kind, path, fmt.Errorf("missing required attribute '%s' for %s type '%s'", key, kind, typeName))
+			}
+			continue
+		}
+
+		errs.Add(kind, path, attribute.check(key, value, fmt.Sprintf(" for %s type '%s'", kind, typeName)))
+	}
+
+	return errs
+}
+
+// validateRelationships returns the problems with the parents of nodes and the nodes
+// connected by links that are not allowed by the type schemas.
+func (types *Types) validateRelationships(config *Config, nodeMap map[string]Node) common.ValidationErrors {
+	var errs common.ValidationErrors
+
+	// Validate the parent of each node is one of the allowed types
+	for i, node := range config.Nodes {
+		schema, exists := types.Nodes[node.Type]
+		if !exists || len(schema.Parents) == 0 {
+			continue
+		}
+
+		path := fmt.Sprintf("nodes[%d].parent", i)
+		allowed := strings.Join(schema.Parents, ", ")
+		if node.Parent == "" {
+			addAt(&errs, "node", path, node.Origin, fmt.Sprintf("node '%s' of type '%s' must have a parent of type: %s", node.ID, node.Type, allowed))
+			continue
+		}
+
+		// Non-existent parents are reported by the parent check
+		parent, exists := nodeMap[node.Parent]
+		if exists && !slices.Contains(schema.Parents, parent.Type) {
+			addAt(&errs, "node", path, node.Origin, fmt.Sprintf("node '%s' of type '%s' cannot have parent '%s' of type '%s', must be one of: %s", node.ID, node.Type, parent.ID, parent.Type, allowed))
+		}
+	}
+
+	// Validate each link connects one of the allowed pairs of node types
+	for i, rel := range config.Links {
+		schema, exists := types.Links[rel.Type]
+		if !exists || len(schema.Connects) == 0 {
+			continue
+		}
+
+		// Non-existent sources and targets are reported by the link check
+		source, sourceExists := nodeMap[rel.Source]
+		target, targetExists := nodeMap[rel.Target]
+		if !sourceExists || !targetExists {
+			continue
+		}
+
+		allowed := false
+		for _, connection := range schema.Connects {
+			if connection.matches(source.Type, target.Type) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			addAt(&errs, "link", fmt.Sprintf("links[%d].type", i), rel.Origin, fmt.Sprintf("link of type '%s' cannot connect '%s' of type '%s' to '%s' of type '%s', must connect: %s", rel.Type, source.ID, source.Type, target.ID, target.Type, formatConnections(schema.Connects)))
+		}
+	}
+
+	return errs
+}
+
+// matches checks if the connection allows a link between the node types.
+func (connection Connection) matches(sourceType string, targetType string) bool {
+	return (connection.Source == "" || connection.Source == sourceType) &&
+		(connection.Target == "" || connection.Target == targetType)
+}
+
+// formatConnections returns the connections as 'Source -> Target' pairs where an
+// empty type is shown as '*'.
+func formatConnections(connections []Connection) string {
+	pairs := make([]string, len(connections))
+	for i, connection := range connections {
+		source, target := connection.Source, connection.Target
+		if source == "" {
+			source = "*"
+		}
+		if target == "" {
+			target = "*"
+		}
+		pairs[i] = fmt.Sprintf("%s -> %s", source, target)
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// check returns an error if the value does not follow the rules of the attribute
+// where context describes what the rules are for such as " for node type 'Service'".
+func (attribute AttributeSchema) check(key string, value any, context string) error {
+	if attribute.Type != "" && attributeType(value) != attribute.Type {
+		return fmt.Errorf("attribute '%s' must be a %s%s", key, attribute.Type, context)
+	}
+
+	// Lists are checked one item at a time
+	items := []any{value}
+	if list, ok := value.([]any); ok {
+		items = list
+	}
+
+	for _, item := range items {
+		formatted := FormatAttributeValue(item)
+
+		if len(attribute.Values) > 0 && !slices.Contains(attribute.Values, formatted) {
+			return fmt.Errorf("attribute '%s' value '%s' is not allowed%s, must be one of: %s", key, formatted, context, strings.Join(attribute.Values, ", "))
+		}
+
+		if attribute.Pattern != "" {
+			re := attribute.pattern
+			if re == nil {
+				// The schemas were not compiled, so compile the pattern for this check
+				var err error
+				re, err = regexp.Compile(attribute.Pattern)
+				if err != nil {
+					return fmt.Errorf("invalid pattern '%s' for attribute '%s': %v", attribute.Pattern, key, err)
+				}
+			}
+			if !re.MatchString(formatted) {
+				return fmt.Errorf("attribute '%s' value '%s' does not match pattern '%s'%s", key, formatted, attribute.Pattern, context)
+			}
+		}
+	}
+
+	return nil
+}
+
+// attributeType returns the schema type name of the value.
+func attributeType(value any) string {
+	switch value.(type) {
+	case bool:
+		return AttributeTypeBoolean
+	case time.Time:
+		// Unquoted dates are decoded as timestamps but are written as text
+		return AttributeTypeString
+	case int, int64, uint64, float64:
+		return AttributeTypeNumber
+	case []any:
+		return AttributeTypeList
+	case map[string]any:
+		return AttributeTypeMap
+	default:
+		return AttributeTypeString
+	}
+}
+
+// sortedKeys returns the keys of the map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,90 @@
+package configuration
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAttributeSchemaCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		attribute AttributeSchema
+		value     any
+		expected  string
+	}{
+		{"string", AttributeSchema{Type: AttributeTypeString}, "Go", ""},
+		{"number", AttributeSchema{Type: AttributeTypeNumber}, 3, ""},
+		{"wrong_type", AttributeSchema{Type: AttributeTypeBoolean}, "yes", "attribute 'key' must be a boolean"},
+		{"allowed_value", AttributeSchema{Values: []string{"1", "2"}}, 2, ""},
+		{"list_values", AttributeSchema{Type: AttributeTypeList, Values: []string{"a", "b"}}, []any{"a", "c"}, "attribute 'key' value 'c' is not allowed, must be one of: a, b"},
+		{"pattern", AttributeSchema{Pattern: "^team-"}, "team-web", ""},
+		{"date", AttributeSchema{Type: AttributeTypeString, Pattern: `^\d{4}-\d{2}-\d{2}$`}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), ""},
+		{"pattern_mismatch", AttributeSchema{Pattern: "^team-"}, "web", "attribute 'key' value 'web' does not match pattern '^team-'"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.attribute.check("key", test.value, "")
+			if test.expected == "" && err != nil {
+				t.Errorf("check() = %v; want nil", err)
+			}
+			if test.expected != "" && (err == nil || err.Error() != test.expected) {
+				t.Errorf("check() = %v; want %q", err, test.expected)
+			}
+		})
+	}
+}
+
+func TestMergeConfigsTypes(t *testing.T) {
+	first := "types:\n  nodes:\n    Service:\n      attributes:\n        owner:\n          required: true\n"
+	second := "types:\n  nodes:\n    Database:\n      attributes:\n        engine:\n          default: PostgreSQL\nnodes:\n  - id: db\n    type: Database\n"
+
+	merged, err := MergeConfigs(parseConfigs(t, first, second)...)
+	if err != nil {
+		t.Fatalf("MergeConfigs() error = %v", err)
+	}
+	if len(merged.Types.Nodes) != 2 {
+		t.Errorf("MergeConfigs() merged %d node types; want 2", len(merged.Types.Nodes))
+	}
+	if merged.Nodes[0].Attributes["engine"] != "PostgreSQL" {
+		t.Errorf("engine = %v; want the default %q", merged.Nodes[0].Attributes["engine"], "PostgreSQL")
+	}
+
+	_, err = MergeConfigs(parseConfigs(t, first, first)...)
+	if err == nil || err.Error() != "duplicate schema for node type 'Service' found" {
+		t.Errorf("MergeConfigs() error = %v; want duplicate schema error", err)
+	}
+}
+
+func TestApplyDefaultsNested(t *testing.T) {
+	content := "types:\n  nodes:\n    Service:\n      attributes:\n        deploy.region:\n          default: us-east-1\n          pattern: \"^[a-z]+-[a-z]+-[0-9]$\"\n        deploy.replicas:\n          default: 2\n        owner.team:\n          default: platform\n" +
+		"nodes:\n  - id: api\n    type: Service\n    attributes:\n      deploy:\n        replicas: 3\n      owner: payments\n"
+
+	config, err := ParseYAML(content)
+	if err != nil {
+		t.Fatalf("ParseYAML() error = %v", err)
+	}
+	err = config.ResolveImports("")
+	if err != nil {
+		t.Fatalf("ResolveImports() error = %v", err)
+	}
+
+	// The default is written into the nested map rather than as a dotted key
+	deploy, ok := config.Nodes[0].Attributes["deploy"].(map[string]any)
+	if !ok || deploy["region"] != "us-east-1" || deploy["replicas"] != 3 {
+		t.Errorf("deploy = %v; want region us-east-1 and replicas 3", config.Nodes[0].Attributes["deploy"])
+	}
+	if _, exists := config.Nodes[0].Attributes["deploy.region"]; exists {
+		t.Errorf("attributes = %v; want no dotted key", config.Nodes[0].Attributes)
+	}
+
+	// A default cannot be nested in an attribute that is not a map
+	if config.Nodes[0].Attributes["owner"] != "payments" {
+		t.Errorf("owner = %v; want %q", config.Nodes[0].Attributes["owner"], "payments")
+	}
+
+	// The pattern is compiled once and kept with the schema
+	if config.Types.Nodes["Service"].Attributes["deploy.region"].pattern == nil {
+		t.Errorf("pattern was not compiled")
+	}
+}
@@ -2,181 +2,179 @@ package configuration
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/UnitVectorY-Labs/YAMLtecture/internal/common"
 )
 
-// ValidateConfig performs all required validations on the configuration.
+// ValidateConfig performs all required validations on the configuration and
+// returns all of the problems found as common.ValidationErrors.
 func (config *Config) Validate() error {
+	var errs common.ValidationErrors
 
-	// Map for storing node IDs
-	nodeMap := make(map[string]bool)
+	// Validate the type schemas
+	types := config.Types
+	if types == nil {
+		types = &Types{}
+	}
+	errs.Merge("schema", "types", types.validate().ErrOrNil())
+
+	// Map for storing the first node defined with each ID
+	nodeMap := make(map[string]Node)
 
 	// Validate nodes
-	for _, node := range config.Nodes {
-		err := node.validate()
-		if err != nil {
-			return fmt.Errorf("node '%s' is invalid: %w", node.ID, err)
+	for i, node := range config.Nodes {
+		path := fmt.Sprintf("nodes[%d]", i)
+		nodeErrs := node.validate()
+		if schema, exists := types.Nodes[node.Type]; exists {
+			nodeErrs = append(nodeErrs, schema.validateAttributes(node.Attributes, "node", node.Type)...)
+		}
+		for _, e := range nodeErrs {
+			addAt(&errs, e.Kind, common.JoinPath(path, e.Path), node.Origin, fmt.Sprintf("node '%s' is invalid: %s", node.ID, e.Message))
 		}
 
-		// Check for duplicate node IDs
-		if _, exists := nodeMap[node.ID]; exists {
-			return fmt.Errorf("duplicate node ID found: '%s'", node.ID)
+		// Check for duplicate node IDs citing where the node was first defined
+		if first, exists := nodeMap[node.ID]; exists {
+			errs = append(errs, common.ValidationError{
+				Kind:    "node",
+				Path:    path + ".id",
+				Message: node.Origin.annotateDuplicate(fmt.Sprintf("duplicate node ID found: '%s'", node.ID), first.Origin),
+				File:    node.Origin.File,
+				Line:    node.Origin.Line,
+				Column:  node.Origin.Column,
+			})
+			continue
 		}
-		nodeMap[node.ID] = true
+		nodeMap[node.ID] = node
 	}
 
 	// Validate links
 	for i, rel := range config.Links {
-		err := rel.validate()
-		if err != nil {
-			return fmt.Errorf("link at index %d is invalid: %w", i, err)
+		path := fmt.Sprintf("links[%d]", i)
+		linkErrs := rel.validate()
+		if schema, exists := types.Links[rel.Type]; exists {
+			linkErrs = append(linkErrs, schema.validateAttributes(rel.Attributes, "link", rel.Type)...)
+		}
+		for _, e := range linkErrs {
+			addAt(&errs, e.Kind, common.JoinPath(path, e.Path), rel.Origin, fmt.Sprintf("link at index %d is invalid: %s", i, e.Message))
 		}
 	}
 
 	// Validate parent links (acyclic)
 	parentMap := make(map[string]string)
-	for _, node := range config.Nodes {
+	for i, node := range config.Nodes {
 		if node.Parent != "" {
 			parentMap[node.ID] = node.Parent
 			// Check if parent exists
-			parentExists := false
-			for _, n := range config.Nodes {
-				if n.ID == node.Parent {
-					parentExists = true
-					break
-				}
-			}
-			if !parentExists {
-				return fmt.Errorf("node '%s' has non-existent parent '%s'", node.ID, node.Parent)
+			if _, exists := nodeMap[node.Parent]; !exists {
+				addAt(&errs, "node", fmt.Sprintf("nodes[%d].parent", i), node.Origin, fmt.Sprintf("node '%s' has non-existent parent '%s'", node.ID, node.Parent))
 			}
 		}
 	}
 
-	// Detect cycles in parent links
+	// Detect cycles in parent links, reporting each cycle once
 	visited := make(map[string]bool)
-	recStack := make(map[string]bool)
+	for i, node := range config.Nodes {
+		if visited[node.ID] {
+			continue
+		}
 
-	for _, node := range config.Nodes {
-		if !visited[node.ID] {
-			if isCyclic(node.ID, parentMap, visited, recStack) {
-				return fmt.Errorf("cycle detected in parent links involving node '%s'", node.ID)
-			}
+		// Walk up the parent chain until reaching a node that was already visited
+		chain := make(map[string]bool)
+		current := node.ID
+		for current != "" && !visited[current] {
+			visited[current] = true
+			chain[current] = true
+			current = parentMap[current]
+		}
+
+		// Reaching a node in the current chain means the chain loops back on itself
+		if current != "" && chain[current] {
+			addAt(&errs, "node", fmt.Sprintf("nodes[%d].parent", i), node.Origin, fmt.Sprintf("cycle detected in parent links involving node '%s'", node.ID))
 		}
 	}
 
+	// Validate the parents and links follow the type schemas
+	errs = append(errs, types.validateRelationships(config, nodeMap)...)
+
 	// Validate links
-	for _, rel := range config.Links {
-		sourceExists := false
-		targetExists := false
-		for _, node := range config.Nodes {
-			if node.ID == rel.Source {
-				sourceExists = true
-			}
-			if node.ID == rel.Target {
-				targetExists = true
-			}
-			if sourceExists && targetExists {
-				break
-			}
-		}
-		if !sourceExists {
-			return fmt.Errorf("link has non-existent source node '%s'", rel.Source)
+	for i, rel := range config.Links {
+		if _, exists := nodeMap[rel.Source]; rel.Source != "" && !exists {
+			addAt(&errs, "link", fmt.Sprintf("links[%d].source", i), rel.Origin, fmt.Sprintf("link has non-existent source node '%s'", rel.Source))
 		}
-		if !targetExists {
-			return fmt.Errorf("link has non-existent target node '%s'", rel.Target)
+		if _, exists := nodeMap[rel.Target]; rel.Target != "" && !exists {
+			addAt(&errs, "link", fmt.Sprintf("links[%d].target", i), rel.Origin, fmt.Sprintf("link has non-existent target node '%s'", rel.Target))
 		}
 	}
 
-	return nil
+	return errs.ErrOrNil()
 }
 
-func (node *Node) validate() error {
-	var err error
+// validate returns the problems with the node with paths relative to the node.
+func (node *Node) validate() common.ValidationErrors {
+	var errs common.ValidationErrors
 
-	err = common.IsValidName(node.ID, "node.id")
-	if err != nil {
-		return err
-	}
-
-	err = common.IsValidName(node.Type, "node.type")
-	if err != nil {
-		return err
-	}
+	errs.Add("node", "id", common.IsValidName(node.ID, "node.id"))
+	errs.Add("node", "type", common.IsValidName(node.Type, "node.type"))
 
 	if node.Parent != "" {
-		err = common.IsValidName(node.Parent, "node.parent")
-		if err != nil {
-			return err
-		}
+		errs.Add("node", "parent", common.IsValidName(node.Parent, "node.parent"))
 	}
 
 	// validate the attribute keys
-	for key, value := range node.Attributes {
-		err = common.IsValidName(key, "attribute.key")
-		if err != nil {
-			return err
-		}
-
-		err = common.IsValidValue(value.(string), "attribute.value")
-		if err != nil {
-			return err
-		}
-	}
+	errs = append(errs, validateAttributes(node.Attributes, "node")...)
 
-	return nil
+	return errs
 }
 
-func (rel *Link) validate() error {
-	var err error
+// validate returns the problems with the link with paths relative to the link.
+func (rel *Link) validate() common.ValidationErrors {
+	var errs common.ValidationErrors
 
-	err = common.IsValidName(rel.Source, "link.source")
-	if err != nil {
-		return err
-	}
+	errs.Add("link", "source", common.IsValidName(rel.Source, "link.source"))
+	errs.Add("link", "target", common.IsValidName(rel.Target, "link.target"))
+	errs.Add("link", "type", common.IsValidName(rel.Type, "link.type"))
 
-	err = common.IsValidName(rel.Target, "link.target")
-	if err != nil {
-		return err
-	}
+	// validate the attribute keys
+	errs = append(errs, validateAttributes(rel.Attributes, "link")...)
+
+	return errs
+}
 
-	err = common.IsValidName(rel.Type, "link.type")
-	if err != nil {
-		return err
+// validateAttributes returns the problems with the attribute keys and values in
+// sorted key order so the output is deterministic.
+func validateAttributes(attributes map[string]any, kind string) common.ValidationErrors {
+	var errs common.ValidationErrors
+
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
 
-	// validate the attribute keys
-	for key, value := range rel.Attributes {
-		err = common.IsValidName(key, "attribute.key")
-		if err != nil {
-			return err
-		}
+	for _, key := range keys {
+		path := common.JoinPath("attributes", key)
 
-		err = common.IsValidValue(value.(string), "attribute.value")
+		err := common.IsValidName(key, "attribute.key")
 		if err != nil {
-			return err
+			errs.Add(kind, path, err)
+			continue
 		}
+
+		errs.Add(kind, path, validateAttributeValue(attributes[key], "attribute."+key))
 	}
 
-	return nil
+	return errs
 }
 
-// isCyclic is a helper function to detect cycles in parent links.
-func isCyclic(node string, parentMap map[string]string, visited, recStack map[string]bool) bool {
-	visited[node] = true
-	recStack[node] = true
-
-	parent, hasParent := parentMap[node]
-	if hasParent {
-		if !visited[parent] {
-			if isCyclic(parent, parentMap, visited, recStack) {
-				return true
-			}
-		} else if recStack[parent] {
-			return true
-		}
-	}
-
-	recStack[node] = false
-	return false
+// addAt records a problem annotated with the location the element was defined at.
+func addAt(errs *common.ValidationErrors, kind string, path string, origin Origin, message string) {
+	*errs = append(*errs, common.ValidationError{
+		Kind:    kind,
+		Path:    path,
+		Message: origin.annotate(message),
+		File:    origin.File,
+		Line:    origin.Line,
+		Column:  origin.Column,
+	})
 }
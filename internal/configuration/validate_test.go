@@ -31,11 +31,19 @@ func TestInvalidConfig(t *testing.T) {
 					t.Fatalf("expected_error.txt file does not exist in %s", path)
 				}
 
-				// Load the configuration
-				config, err := LoadConfig(inputFile)
+				// Parse the configuration the same way the CLI does so the locations cite the input file
+				content, err := os.ReadFile(inputFile)
+				if err != nil {
+					t.Fatalf("Failed to read %s: %v", inputFile, err)
+				}
+				config, err := ParseYAML(string(content))
 				if err != nil {
 					t.Fatalf("Failed to load %s: %v", inputFile, err)
 				}
+				err = config.ResolveImports(inputFile)
+				if err != nil {
+					t.Fatalf("Failed to resolve %s: %v", inputFile, err)
+				}
 
 				// Validate the configuration
 				err = config.Validate()
@@ -43,7 +51,8 @@ func TestInvalidConfig(t *testing.T) {
 					t.Fatalf("Expected validation error for %s, but got none", inputFile)
 				}
 
-				actualErrorStr := "YAMLtecture\nError: Error validating configuration\n" + strings.TrimSpace(err.Error())
+				// The CLI is run from the root of the repository
+				actualErrorStr := "YAMLtecture\nError: Error validating configuration\n" + strings.TrimSpace(strings.ReplaceAll(err.Error(), "../../", ""))
 
 				// Read the expected error message
 				expectedError, err := os.ReadFile(expectedErrorFile)
@@ -0,0 +1,320 @@
+package d2
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/diagram"
+	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
+)
+
+// directions maps the diagram directions to the D2 direction keywords
+var directions = map[string]string{
+	"TB": "down",
+	"BT": "up",
+	"LR": "right",
+	"RL": "left",
+}
+
+// defaultShapes are the shapes of the node types the settings do not define a shape for
+var defaultShapes = map[string]string{
+	"Database": "cylinder",
+	"Queue":    "queue",
+}
+
+// unquotedKeyRegex matches the keys that can be written without quotes
+var unquotedKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// D2 contains the settings for generating the D2 diagram.
+type D2 struct {
+	// The direction of the diagram (TB, BT, LR, RL)
+	Direction string `yaml:"direction"`
+	// The attribute to use as the node label (if set)
+	NodeLabel string `yaml:"nodeLabel"`
+	// The attribute to use as the link label instead of the link type (if set)
+	LinkLabel string `yaml:"linkLabel,omitempty"`
+	// The query to identify nodes to render as containers, if not set every node with children is a container
+	ContainerNodes query.Nodes `yaml:"containerNodes,omitempty"`
+	// The D2 shape to use for each node type
+	Shapes map[string]string `yaml:"shapes,omitempty"`
+	// The style to apply to nodes
+	NodeStyle []NodeStyle `yaml:"nodeStyles,omitempty"`
+	// The style to apply to links
+	LinkStyle []LinkStyle `yaml:"linkStyles,omitempty"`
+}
+
+type NodeStyle struct {
+	// The query to identify nodes to format with the style
+	Filters []query.Filter `yaml:"filters"`
+	// The style to apply to the nodes
+	Format NodeStyleFormat `yaml:"format"`
+}
+
+type NodeStyleFormat struct {
+	Shape        string `yaml:"shape,omitempty"`
+	Fill         string `yaml:"fill,omitempty"`
+	Stroke       string `yaml:"stroke,omitempty"`
+	StrokeWidth  string `yaml:"strokeWidth,omitempty"`
+	StrokeDash   string `yaml:"strokeDash,omitempty"`
+	FontColor    string `yaml:"fontColor,omitempty"`
+	BorderRadius string `yaml:"borderRadius,omitempty"`
+}
+
+type LinkStyle struct {
+	// The query to identify links to format with the style
+	Filters []query.Filter `yaml:"filters"`
+	// The style to apply to the links
+	Format LinkStyleFormat `yaml:"format"`
+}
+
+type LinkStyleFormat struct {
+	Stroke      string `yaml:"stroke,omitempty"`
+	StrokeWidth string `yaml:"strokeWidth,omitempty"`
+	StrokeDash  string `yaml:"strokeDash,omitempty"`
+	FontColor   string `yaml:"fontColor,omitempty"`
+}
+
+// GenerateD2 creates a D2 diagram from the config and D2 settings. Nodes are
+// placed inside the container of their nearest ancestor that is rendered as a
+// container.
+func GenerateD2(config *configuration.Config, setting *D2) (string, error) {
+	var d2 strings.Builder
+
+	// Write the header.
+	d2.WriteString(fmt.Sprintf("direction: %s\n", directions[setting.Direction]))
+
+	// Build a lookup for nodes.
+	nodeLookup := make(map[string]configuration.Node)
+	for _, node := range config.Nodes {
+		nodeLookup[node.ID] = node
+	}
+
+	// Determine which nodes are containers, only nodes with children can be a container.
+	candidates := config.Nodes
+	if len(setting.ContainerNodes.Filters) > 0 {
+		containerConfig, err := query.ExecuteQuery(&query.Query{Nodes: setting.ContainerNodes}, config)
+		if err != nil {
+			return "", fmt.Errorf("error executing container query: %v", err)
+		}
+		candidates = containerConfig.Nodes
+	}
+	hasChildren := diagram.ParentIDs(config)
+	containers := make(map[string]bool)
+	for _, node := range candidates {
+		containers[node.ID] = hasChildren[node.ID]
+	}
+
+	// Place the containers and nodes in their nearest container ancestor.
+	tree := diagram.NewTree(config, containers)
+
+	// Helper: the path of a node through the containers it is rendered in, such as 'cloud.vpc.web'.
+	path := func(nodeID string) string {
+		keys := []string{d2Key(nodeID)}
+		for cur := tree.Ancestor(nodeID); cur != ""; cur = tree.Ancestor(cur) {
+			keys = append([]string{d2Key(cur)}, keys...)
+		}
+		return strings.Join(keys, ".")
+	}
+
+	// Determine the style of each node, the shape of the node type is applied first
+	// and later styles take precedence.
+	shapes := make(map[string]string)
+	for nodeType, shape := range defaultShapes {
+		shapes[nodeType] = shape
+	}
+	for nodeType, shape := range setting.Shapes {
+		shapes[nodeType] = shape
+	}
+	nodeFormats := make(map[string]*NodeStyleFormat)
+	for _, node := range config.Nodes {
+		nodeFormats[node.ID] = &NodeStyleFormat{Shape: shapes[node.Type]}
+		if node.External {
+			nodeFormats[node.ID].StrokeDash = "3"
+		}
+	}
+	for _, style := range setting.NodeStyle {
+		nodes, err := query.ExecuteQuery(&query.Query{Nodes: query.Nodes{Filters: style.Filters}}, config)
+		if err != nil {
+			return "", fmt.Errorf("error executing node style query: %v", err)
+		}
+		for _, node := range nodes.Nodes {
+			nodeFormats[node.ID].merge(style.Format)
+		}
+	}
+
+	// Helper: the declaration of a node such as 'web: "Web Server"', nodes without
+	// a label need an empty label before a body.
+	declaration := func(nodeID string, hasBody bool) string {
+		if val := configuration.NodeAttributeLabel(nodeLookup[nodeID], setting.NodeLabel); val != "" {
+			return fmt.Sprintf("%s: %s", d2Key(nodeID), quote(val))
+		}
+		if hasBody {
+			return d2Key(nodeID) + ":"
+		}
+		return d2Key(nodeID)
+	}
+
+	d2.WriteString("\n")
+	d2.WriteString("# Nodes\n")
+
+	// Helper to output a node with its style and the body of a container.
+	outputNode := func(nodeID string, indent string, body func(indent string)) {
+		properties := nodeFormats[nodeID].properties()
+		if len(properties) == 0 && body == nil {
+			d2.WriteString(fmt.Sprintf("%s%s\n", indent, declaration(nodeID, false)))
+			return
+		}
+
+		d2.WriteString(fmt.Sprintf("%s%s {\n", indent, declaration(nodeID, true)))
+		for _, property := range properties {
+			d2.WriteString(fmt.Sprintf("%s  %s\n", indent, property))
+		}
+		if body != nil {
+			body(indent + "  ")
+		}
+		d2.WriteString(fmt.Sprintf("%s}\n", indent))
+	}
+
+	// Recursive helper to output a container.
+	var outputContainer func(c *diagram.Container, indent string)
+	outputContainer = func(c *diagram.Container, indent string) {
+		outputNode(c.ID, indent, func(indent string) {
+			for _, nodeID := range c.Nodes {
+				outputNode(nodeID, indent, nil)
+			}
+			for _, sub := range c.Containers {
+				outputContainer(sub, indent)
+			}
+		})
+	}
+
+	for _, c := range tree.Containers {
+		outputContainer(c, "")
+	}
+	for _, nodeID := range tree.Nodes {
+		outputNode(nodeID, "", nil)
+	}
+
+	// Determine the style of each link, later styles take precedence.
+	linkFormats := make(map[string]*LinkStyleFormat)
+	for _, link := range config.Links {
+		linkFormats[link.ID] = &LinkStyleFormat{}
+	}
+	for _, style := range setting.LinkStyle {
+		links, err := query.ExecuteQuery(&query.Query{Links: query.Links{Filters: style.Filters}}, config)
+		if err != nil {
+			return "", fmt.Errorf("error executing link style query: %v", err)
+		}
+		for _, link := range links.Links {
+			linkFormats[link.ID].merge(style.Format)
+		}
+	}
+
+	// Output the links sorted by their source and target.
+	links := diagram.SortLinks(config.Links)
+	if len(links) > 0 {
+		d2.WriteString("\n")
+		d2.WriteString("# Links\n")
+	}
+	for _, link := range links {
+		label := link.Type
+		if setting.LinkLabel != "" {
+			if val, exists := configuration.LookupAttribute(link.Attributes, setting.LinkLabel); exists {
+				label = configuration.FormatAttributeValue(val)
+			}
+		}
+
+		edge := fmt.Sprintf("%s -> %s: %s", path(link.Source), path(link.Target), quote(label))
+		properties := linkFormats[link.ID].properties()
+		if len(properties) == 0 {
+			d2.WriteString(edge + "\n")
+			continue
+		}
+
+		d2.WriteString(edge + " {\n")
+		for _, property := range properties {
+			d2.WriteString(fmt.Sprintf("  %s\n", property))
+		}
+		d2.WriteString("}\n")
+	}
+
+	return d2.String(), nil
+}
+
+// d2Key returns the node ID as a D2 key, quoting IDs with characters such as
+// the '/' in namespaced IDs or a '.' that D2 would treat as nesting.
+func d2Key(id string) string {
+	if unquotedKeyRegex.MatchString(id) {
+		return id
+	}
+	return quote(id)
+}
+
+// quote returns the value as a quoted D2 string, escaping the quotes and backslashes.
+func quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
+
+// merge sets the attributes of the other format that are set.
+func (f *NodeStyleFormat) merge(other NodeStyleFormat) {
+	diagram.SetIfNotEmpty(&f.Shape, other.Shape)
+	diagram.SetIfNotEmpty(&f.Fill, other.Fill)
+	diagram.SetIfNotEmpty(&f.Stroke, other.Stroke)
+	diagram.SetIfNotEmpty(&f.StrokeWidth, other.StrokeWidth)
+	diagram.SetIfNotEmpty(&f.StrokeDash, other.StrokeDash)
+	diagram.SetIfNotEmpty(&f.FontColor, other.FontColor)
+	diagram.SetIfNotEmpty(&f.BorderRadius, other.BorderRadius)
+}
+
+// properties returns the D2 properties of the node such as 'style.fill: "#DDEEFF"'.
+func (f NodeStyleFormat) properties() []string {
+	props := []string{}
+	if f.Shape != "" {
+		props = append(props, fmt.Sprintf("shape: %s", f.Shape))
+	}
+	props = appendStyle(props, "fill", quoteColor(f.Fill))
+	props = appendStyle(props, "stroke", quoteColor(f.Stroke))
+	props = appendStyle(props, "stroke-width", f.StrokeWidth)
+	props = appendStyle(props, "stroke-dash", f.StrokeDash)
+	props = appendStyle(props, "font-color", quoteColor(f.FontColor))
+	props = appendStyle(props, "border-radius", f.BorderRadius)
+	return props
+}
+
+// merge sets the attributes of the other format that are set.
+func (l *LinkStyleFormat) merge(other LinkStyleFormat) {
+	diagram.SetIfNotEmpty(&l.Stroke, other.Stroke)
+	diagram.SetIfNotEmpty(&l.StrokeWidth, other.StrokeWidth)
+	diagram.SetIfNotEmpty(&l.StrokeDash, other.StrokeDash)
+	diagram.SetIfNotEmpty(&l.FontColor, other.FontColor)
+}
+
+// properties returns the D2 properties of the link such as 'style.stroke-dash: 3'.
+func (l LinkStyleFormat) properties() []string {
+	props := []string{}
+	props = appendStyle(props, "stroke", quoteColor(l.Stroke))
+	props = appendStyle(props, "stroke-width", l.StrokeWidth)
+	props = appendStyle(props, "stroke-dash", l.StrokeDash)
+	props = appendStyle(props, "font-color", quoteColor(l.FontColor))
+	return props
+}
+
+// appendStyle appends the style property if the value is set.
+func appendStyle(props []string, name string, value string) []string {
+	if value == "" {
+		return props
+	}
+	return append(props, fmt.Sprintf("style.%s: %s", name, value))
+}
+
+// quoteColor quotes a color as the '#' would otherwise start a D2 comment.
+func quoteColor(color string) string {
+	if color == "" {
+		return ""
+	}
+	return quote(color)
+}
@@ -0,0 +1,160 @@
+package d2
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+)
+
+func TestGenerateD2(t *testing.T) {
+	err := filepath.Walk("../../tests", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			configPath := filepath.Join(path, "config.yaml")
+			d2ConfigPath := filepath.Join(path, "d2.yaml")
+			d2Path := filepath.Join(path, "d2.d2")
+
+			if _, err := os.Stat(configPath); os.IsNotExist(err) {
+				return nil
+			}
+			if _, err := os.Stat(d2ConfigPath); os.IsNotExist(err) {
+				return nil
+			}
+			if _, err := os.Stat(d2Path); os.IsNotExist(err) {
+				return nil
+			}
+
+			relDir, err := filepath.Rel("../../tests", path)
+			if err != nil {
+				return err
+			}
+
+			sanitizedRelDir := strings.ReplaceAll(relDir, string(filepath.Separator), "#")
+
+			t.Run(sanitizedRelDir, func(t *testing.T) {
+				config, err := configuration.LoadConfig(configPath)
+				if err != nil {
+					t.Fatalf("Failed to load config: %v", err)
+				}
+
+				d2Config, err := LoadD2(d2ConfigPath)
+				if err != nil {
+					t.Fatalf("Failed to load d2 config: %v", err)
+				}
+
+				err = d2Config.Validate()
+				if err != nil {
+					t.Fatalf("D2 config validation failed: %v", err)
+				}
+
+				expectedBytes, err := os.ReadFile(d2Path)
+				if err != nil {
+					t.Fatalf("Failed to read D2 file: %v", err)
+				}
+				expectedOutput := string(expectedBytes)
+
+				output, err := GenerateD2(config, d2Config)
+				if err != nil {
+					t.Fatalf("GenerateD2 returned error: %v", err)
+				}
+				if output != expectedOutput {
+					t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
+				}
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error walking through example folder: %v", err)
+	}
+}
+
+func TestGenerateD2Paths(t *testing.T) {
+	config, err := configuration.ParseYAML(`
+nodes:
+  - id: platform
+    type: System
+  - id: payments/api
+    type: Service
+    parent: platform
+  - id: payments/db
+    type: Database
+    parent: platform
+links:
+  - source: payments/api
+    target: payments/db
+    type: SQL
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	setting, err := ParseYAML("")
+	if err != nil {
+		t.Fatalf("Failed to parse settings: %v", err)
+	}
+
+	output, err := GenerateD2(config, setting)
+	if err != nil {
+		t.Fatalf("GenerateD2 returned error: %v", err)
+	}
+
+	// Namespaced IDs are quoted and links reference the nodes through their container
+	expected := []string{
+		"platform: {",
+		`  "payments/db": {`,
+		"    shape: cylinder",
+		`platform."payments/api" -> platform."payments/db": "SQL"`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, output)
+		}
+	}
+}
+
+func TestGenerateD2Shapes(t *testing.T) {
+	config, err := configuration.ParseYAML(`
+nodes:
+  - id: api
+    type: Service
+  - id: db
+    type: Database
+  - id: events
+    type: Queue
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	setting, err := ParseYAML(`
+shapes:
+  Service: hexagon
+  Queue: stored_data
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse settings: %v", err)
+	}
+
+	output, err := GenerateD2(config, setting)
+	if err != nil {
+		t.Fatalf("GenerateD2 returned error: %v", err)
+	}
+
+	// The shapes in the settings are merged over the default shapes
+	expected := []string{
+		"api: {\n  shape: hexagon\n}",
+		"db: {\n  shape: cylinder\n}",
+		"events: {\n  shape: stored_data\n}",
+	}
+	for _, line := range expected {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, output)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package d2
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseYAML parses the YAML content into a D2
+func ParseYAML(content string) (*D2, error) {
+	var config D2
+	err := yaml.Unmarshal([]byte(content), &config)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling YAML: %v", err)
+	}
+
+	// Specify the default values if they were not provided
+
+	if config.Direction == "" {
+		config.Direction = "TB"
+	}
+
+	return &config, nil
+}
+
+// LoadD2 loads and parses a single YAML D2 setting file from the given path.
+func LoadD2(filePath string) (*D2, error) {
+
+	// Read the file contents to a string
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %v", err)
+	}
+
+	// Parse the YAML
+	return ParseYAML(string(data))
+}
@@ -0,0 +1,143 @@
+package d2
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/common"
+	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
+)
+
+// shapes are the D2 shapes that can be used for a node
+var shapes = []string{
+	"rectangle", "square", "page", "parallelogram", "document", "cylinder", "queue", "package",
+	"step", "callout", "stored_data", "person", "diamond", "oval", "circle", "hexagon", "cloud",
+}
+
+// Validate checks if the D2 settings are valid, returning all of the problems
+// found as common.ValidationErrors.
+func (d *D2) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the direction is valid
+	if _, exists := directions[d.Direction]; !exists {
+		errs.Add("setting", "direction", fmt.Errorf("invalid direction: %s", d.Direction))
+	}
+
+	// Validate the node label is valid
+	if d.NodeLabel != "" {
+		// Perform same validation as attribute values
+		errs.Add("setting", "nodeLabel", common.IsValidValue(d.NodeLabel, "nodeLabel"))
+	}
+
+	// Validate the link label is valid
+	if d.LinkLabel != "" {
+		// Perform same validation as attribute values
+		errs.Add("setting", "linkLabel", common.IsValidValue(d.LinkLabel, "linkLabel"))
+	}
+
+	// Validate the container nodes are valid
+	errs.Merge("condition", "containerNodes", d.ContainerNodes.Validate())
+
+	// Validate the shapes for the node types
+	nodeTypes := make([]string, 0, len(d.Shapes))
+	for nodeType := range d.Shapes {
+		nodeTypes = append(nodeTypes, nodeType)
+	}
+	sort.Strings(nodeTypes)
+	for _, nodeType := range nodeTypes {
+		if !slices.Contains(shapes, d.Shapes[nodeType]) {
+			errs.Add("setting", fmt.Sprintf("shapes.%s", nodeType), fmt.Errorf("invalid shape '%s' for node type '%s'", d.Shapes[nodeType], nodeType))
+		}
+	}
+
+	// Validate all of the node styles
+	for i := range d.NodeStyle {
+		errs.Merge("nodeStyle", fmt.Sprintf("nodeStyles[%d]", i), d.NodeStyle[i].Validate())
+	}
+
+	// Validate all of the link styles
+	for i := range d.LinkStyle {
+		errs.Merge("linkStyle", fmt.Sprintf("linkStyles[%d]", i), d.LinkStyle[i].Validate())
+	}
+
+	return errs.ErrOrNil()
+}
+
+func (n *NodeStyle) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the filters are valid
+	for i := range n.Filters {
+		errs.Merge("condition", fmt.Sprintf("filters[%d]", i), n.Filters[i].Validate(query.NodeCondition))
+	}
+
+	// Validate the format is valid
+	errs.Merge("nodeStyle", "format", n.Format.Validate())
+
+	return errs.ErrOrNil()
+}
+
+// Validate checks if the node style format is valid, returning the problems
+// with every field as common.ValidationErrors.
+func (n *NodeStyleFormat) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the shape is valid
+	if n.Shape != "" && !slices.Contains(shapes, n.Shape) {
+		errs.Add("nodeStyle", "shape", fmt.Errorf("invalid shape: '%s'", n.Shape))
+	}
+
+	// Validate the colors are valid
+	errs.Add("nodeStyle", "fill", common.IsValidColor("fill", n.Fill))
+	errs.Add("nodeStyle", "stroke", common.IsValidColor("stroke", n.Stroke))
+	errs.Add("nodeStyle", "fontColor", common.IsValidColor("fontColor", n.FontColor))
+
+	// Validate the sizes are valid numbers
+	errs.Add("nodeStyle", "strokeWidth", common.IsValidNumber("strokeWidth", n.StrokeWidth))
+	errs.Add("nodeStyle", "strokeDash", common.IsValidNumber("strokeDash", n.StrokeDash))
+	errs.Add("nodeStyle", "borderRadius", common.IsValidNumber("borderRadius", n.BorderRadius))
+
+	// Ensure at least one attribute is set
+	if *n == (NodeStyleFormat{}) {
+		errs.Add("nodeStyle", "", fmt.Errorf("at least one 'format' attribute must be set"))
+	}
+
+	return errs.ErrOrNil()
+}
+
+func (l *LinkStyle) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the filters are valid
+	for i := range l.Filters {
+		errs.Merge("condition", fmt.Sprintf("filters[%d]", i), l.Filters[i].Validate(query.LinkCondition))
+	}
+
+	// Validate the format is valid
+	errs.Merge("linkStyle", "format", l.Format.Validate())
+
+	return errs.ErrOrNil()
+}
+
+// Validate checks if the link style format is valid, returning the problems
+// with every field as common.ValidationErrors.
+func (l *LinkStyleFormat) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the colors are valid
+	errs.Add("linkStyle", "stroke", common.IsValidColor("stroke", l.Stroke))
+	errs.Add("linkStyle", "fontColor", common.IsValidColor("fontColor", l.FontColor))
+
+	// Validate the sizes are valid numbers
+	errs.Add("linkStyle", "strokeWidth", common.IsValidNumber("strokeWidth", l.StrokeWidth))
+	errs.Add("linkStyle", "strokeDash", common.IsValidNumber("strokeDash", l.StrokeDash))
+
+	// Ensure at least one attribute is set
+	if *l == (LinkStyleFormat{}) {
+		errs.Add("linkStyle", "", fmt.Errorf("at least one 'format' attribute must be set"))
+	}
+
+	return errs.ErrOrNil()
+}
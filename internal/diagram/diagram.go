@@ -0,0 +1,144 @@
+package diagram
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+)
+
+// Container is a node rendered as a container with the containers and nodes nested inside it.
+type Container struct {
+	ID         string
+	Containers []*Container
+	Nodes      []string
+}
+
+// Tree holds the nodes of a config placed inside of their nearest ancestor that
+// is rendered as a container.
+type Tree struct {
+	// The containers and nodes not nested inside of a container
+	Containers []*Container
+	Nodes      []string
+
+	containers map[string]*Container
+	nodeLookup map[string]configuration.Node
+}
+
+// NewTree places the nodes of the config inside of their nearest ancestor in the
+// set of containers, sorting the containers and nodes by ID for deterministic
+// output. External stub nodes are always rendered as nodes.
+func NewTree(config *configuration.Config, containers map[string]bool) *Tree {
+	tree := &Tree{
+		containers: make(map[string]*Container),
+		nodeLookup: make(map[string]configuration.Node),
+	}
+	for _, node := range config.Nodes {
+		tree.nodeLookup[node.ID] = node
+		if containers[node.ID] && !node.External {
+			tree.containers[node.ID] = &Container{ID: node.ID}
+		}
+	}
+
+	for _, node := range config.Nodes {
+		ancestor := tree.Ancestor(node.ID)
+		if c, isContainer := tree.containers[node.ID]; isContainer {
+			if ancestor != "" {
+				tree.containers[ancestor].Containers = append(tree.containers[ancestor].Containers, c)
+			} else {
+				tree.Containers = append(tree.Containers, c)
+			}
+		} else if ancestor != "" {
+			tree.containers[ancestor].Nodes = append(tree.containers[ancestor].Nodes, node.ID)
+		} else {
+			tree.Nodes = append(tree.Nodes, node.ID)
+		}
+	}
+
+	sortContainers(tree.Containers)
+	sort.Strings(tree.Nodes)
+
+	return tree
+}
+
+// Container returns the container rendered for the node, or nil if the node is
+// not rendered as a container.
+func (t *Tree) Container(nodeID string) *Container {
+	return t.containers[nodeID]
+}
+
+// Ancestor returns the ID of the nearest container the node is rendered inside
+// of, or an empty string if the node is at the top level.
+func (t *Tree) Ancestor(nodeID string) string {
+	for cur := t.nodeLookup[nodeID].Parent; cur != ""; cur = t.nodeLookup[cur].Parent {
+		if t.containers[cur] != nil {
+			return cur
+		}
+	}
+	return ""
+}
+
+// sortContainers sorts the containers and their nested containers and nodes by ID.
+func sortContainers(containers []*Container) {
+	sort.Slice(containers, func(i, j int) bool {
+		return containers[i].ID < containers[j].ID
+	})
+	for _, c := range containers {
+		sort.Strings(c.Nodes)
+		sortContainers(c.Containers)
+	}
+}
+
+// ParentIDs returns the IDs of the nodes that have children.
+func ParentIDs(config *configuration.Config) map[string]bool {
+	parents := make(map[string]bool)
+	for _, node := range config.Nodes {
+		if node.Parent != "" {
+			parents[node.Parent] = true
+		}
+	}
+	return parents
+}
+
+// CheckIDs returns an error if two nodes are rendered with the same identifier
+// after the format replaces the characters it does not allow in their IDs, such
+// as 'payments/api' and 'payments__api' both becoming 'payments__api'.
+func CheckIDs(config *configuration.Config, format func(id string) string) error {
+	rendered := make(map[string]string)
+	for _, node := range config.Nodes {
+		id := format(node.ID)
+		if other, exists := rendered[id]; exists && other != node.ID {
+			return fmt.Errorf("node IDs '%s' and '%s' are both rendered as '%s'", other, node.ID, id)
+		}
+		rendered[id] = node.ID
+	}
+	return nil
+}
+
+// SortLinks returns a copy of the links sorted by their source and target.
+func SortLinks(links []configuration.Link) []configuration.Link {
+	sorted := make([]configuration.Link, len(links))
+	copy(sorted, links)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Source == sorted[j].Source {
+			return sorted[i].Target < sorted[j].Target
+		}
+		return sorted[i].Source < sorted[j].Source
+	})
+	return sorted
+}
+
+// SetIfNotEmpty sets the field to the value if the value is set, used to merge
+// the formats of the styles where later styles take precedence.
+func SetIfNotEmpty(field *string, value string) {
+	if value != "" {
+		*field = value
+	}
+}
+
+// ReplaceQuotes replaces the double quotes that would end a quoted label with
+// single quotes for the formats that cannot escape them.
+func ReplaceQuotes(label string) string {
+	return strings.ReplaceAll(label, `"`, `'`)
+}
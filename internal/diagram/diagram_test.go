@@ -0,0 +1,89 @@
+package diagram
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+)
+
+func TestNewTree(t *testing.T) {
+	config := &configuration.Config{
+		Nodes: []configuration.Node{
+			{ID: "web", Parent: "vpc"},
+			{ID: "vpc", Parent: "cloud"},
+			{ID: "db", Parent: "subnet"},
+			{ID: "subnet", Parent: "vpc"},
+			{ID: "cloud"},
+			{ID: "user"},
+			{ID: "stub", External: true},
+		},
+	}
+
+	// The subnet is not a container so the db is placed in the vpc
+	tree := NewTree(config, map[string]bool{"cloud": true, "vpc": true, "stub": true})
+
+	if !reflect.DeepEqual(tree.Nodes, []string{"stub", "user"}) {
+		t.Errorf("top level nodes = %v; want [stub user]", tree.Nodes)
+	}
+	if len(tree.Containers) != 1 || tree.Containers[0].ID != "cloud" {
+		t.Fatalf("top level containers = %v; want [cloud]", tree.Containers)
+	}
+
+	vpc := tree.Container("vpc")
+	if vpc == nil || !reflect.DeepEqual(vpc.Nodes, []string{"db", "subnet", "web"}) {
+		t.Errorf("vpc nodes = %v; want [db subnet web]", vpc)
+	}
+	if tree.Container("stub") != nil {
+		t.Errorf("external stub node should not be a container")
+	}
+
+	if ancestor := tree.Ancestor("db"); ancestor != "vpc" {
+		t.Errorf("Ancestor(db) = %q; want vpc", ancestor)
+	}
+	if ancestor := tree.Ancestor("cloud"); ancestor != "" {
+		t.Errorf("Ancestor(cloud) = %q; want empty", ancestor)
+	}
+}
+
+func TestSortLinks(t *testing.T) {
+	links := []configuration.Link{
+		{ID: "3", Source: "b", Target: "a"},
+		{ID: "2", Source: "a", Target: "c"},
+		{ID: "1", Source: "a", Target: "b"},
+	}
+
+	sorted := SortLinks(links)
+
+	var ids []string
+	for _, link := range sorted {
+		ids = append(ids, link.ID)
+	}
+	if !reflect.DeepEqual(ids, []string{"1", "2", "3"}) {
+		t.Errorf("SortLinks order = %v; want [1 2 3]", ids)
+	}
+	if links[0].ID != "3" {
+		t.Errorf("SortLinks should not reorder the input links")
+	}
+}
+
+func TestCheckIDs(t *testing.T) {
+	format := func(id string) string {
+		return strings.ReplaceAll(id, "/", "__")
+	}
+
+	config := &configuration.Config{
+		Nodes: []configuration.Node{{ID: "payments/api"}, {ID: "payments/db"}},
+	}
+	if err := CheckIDs(config, format); err != nil {
+		t.Errorf("CheckIDs returned an unexpected error: %v", err)
+	}
+
+	config.Nodes = append(config.Nodes, configuration.Node{ID: "payments__api"})
+	err := CheckIDs(config, format)
+	expected := "node IDs 'payments/api' and 'payments__api' are both rendered as 'payments__api'"
+	if err == nil || err.Error() != expected {
+		t.Errorf("CheckIDs error = %v; want %q", err, expected)
+	}
+}
@@ -0,0 +1,264 @@
+package dot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/diagram"
+	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
+)
+
+// Dot contains the settings for generating the Graphviz diagram.
+type Dot struct {
+	// The direction of the graph (TB, BT, LR, RL)
+	Direction string `yaml:"direction"`
+	// The attribute to use as the node label (if set)
+	NodeLabel string `yaml:"nodeLabel"`
+	// The query to identify nodes to render as clusters, if not set every node with children is a cluster
+	ClusterNodes query.Nodes `yaml:"clusterNodes,omitempty"`
+	// The style to apply to nodes
+	NodeStyle []NodeStyle `yaml:"nodeStyles,omitempty"`
+	// The style to apply to links
+	LinkStyle []LinkStyle `yaml:"linkStyles,omitempty"`
+}
+
+type NodeStyle struct {
+	// The query to identify nodes to format with the style
+	Filters []query.Filter `yaml:"filters"`
+	// The style to apply to the nodes
+	Format NodeStyleFormat `yaml:"format"`
+}
+
+type NodeStyleFormat struct {
+	Shape     string `yaml:"shape,omitempty"`
+	Style     string `yaml:"style,omitempty"`
+	Color     string `yaml:"color,omitempty"`
+	FillColor string `yaml:"fillcolor,omitempty"`
+	FontColor string `yaml:"fontcolor,omitempty"`
+	FontSize  string `yaml:"fontsize,omitempty"`
+	PenWidth  string `yaml:"penwidth,omitempty"`
+}
+
+type LinkStyle struct {
+	// The query to identify links to format with the style
+	Filters []query.Filter `yaml:"filters"`
+	// The style to apply to the links
+	Format LinkStyleFormat `yaml:"format"`
+}
+
+type LinkStyleFormat struct {
+	Style     string `yaml:"style,omitempty"`
+	Color     string `yaml:"color,omitempty"`
+	PenWidth  string `yaml:"penwidth,omitempty"`
+	ArrowHead string `yaml:"arrowhead,omitempty"`
+}
+
+// GenerateDot creates a Graphviz DOT diagram from the config and dot settings.
+// Nodes are placed inside the cluster of their nearest ancestor that is rendered
+// as a cluster.
+func GenerateDot(config *configuration.Config, setting *Dot) (string, error) {
+	var dot strings.Builder
+
+	// Write the header.
+	dot.WriteString("digraph G {\n")
+	dot.WriteString(fmt.Sprintf("    rankdir=%s;\n", setting.Direction))
+	dot.WriteString("    compound=true;\n")
+	dot.WriteString("    node [shape=box];\n")
+
+	// Determine which nodes are clusters, only nodes with children can be a cluster.
+	candidates := config.Nodes
+	if len(setting.ClusterNodes.Filters) > 0 {
+		clusterConfig, err := query.ExecuteQuery(&query.Query{Nodes: setting.ClusterNodes}, config)
+		if err != nil {
+			return "", fmt.Errorf("error executing cluster query: %v", err)
+		}
+		candidates = clusterConfig.Nodes
+	}
+	hasChildren := diagram.ParentIDs(config)
+	clusters := make(map[string]bool)
+	for _, node := range candidates {
+		clusters[node.ID] = hasChildren[node.ID]
+	}
+
+	// Place the clusters and nodes in their nearest cluster ancestor.
+	tree := diagram.NewTree(config, clusters)
+
+	// Determine the label and style of each node, later styles take precedence.
+	nodeLabels := make(map[string]string)
+	nodeFormats := make(map[string]*NodeStyleFormat)
+	for _, node := range config.Nodes {
+		nodeLabels[node.ID] = configuration.NodeAttributeLabel(node, setting.NodeLabel)
+		nodeFormats[node.ID] = &NodeStyleFormat{}
+		if node.External {
+			nodeFormats[node.ID].Style = "dashed"
+		}
+	}
+	for _, style := range setting.NodeStyle {
+		nodes, err := query.ExecuteQuery(&query.Query{Nodes: query.Nodes{Filters: style.Filters}}, config)
+		if err != nil {
+			return "", fmt.Errorf("error executing node style query: %v", err)
+		}
+		for _, node := range nodes.Nodes {
+			nodeFormats[node.ID].merge(style.Format)
+		}
+	}
+
+	// Helper: the DOT statement for a node with its label and style.
+	nodeElement := func(nodeID string) string {
+		attributes := nodeFormats[nodeID].attributes()
+		attributes["label"] = nodeLabels[nodeID]
+		return formatElement(quote(nodeID), attributes, nodeAttributeOrder)
+	}
+
+	dot.WriteString("\n")
+	dot.WriteString("    // Nodes\n")
+
+	// Recursive helper to output a cluster.
+	var outputCluster func(c *diagram.Container, indent string)
+	outputCluster = func(c *diagram.Container, indent string) {
+		label := c.ID
+		if nodeLabels[c.ID] != "" {
+			label = nodeLabels[c.ID]
+		}
+
+		dot.WriteString(fmt.Sprintf("%ssubgraph %s {\n", indent, quote("cluster_"+c.ID)))
+		dot.WriteString(fmt.Sprintf("%s    label=%s;\n", indent, quote(label)))
+		for _, nodeID := range c.Nodes {
+			dot.WriteString(fmt.Sprintf("%s    %s;\n", indent, nodeElement(nodeID)))
+		}
+		for _, sub := range c.Containers {
+			outputCluster(sub, indent+"    ")
+		}
+		dot.WriteString(fmt.Sprintf("%s}\n", indent))
+	}
+
+	for _, c := range tree.Containers {
+		outputCluster(c, "    ")
+	}
+	for _, nodeID := range tree.Nodes {
+		dot.WriteString(fmt.Sprintf("    %s;\n", nodeElement(nodeID)))
+	}
+
+	// Determine the style of each link, later styles take precedence.
+	linkFormats := make(map[string]*LinkStyleFormat)
+	for _, link := range config.Links {
+		linkFormats[link.ID] = &LinkStyleFormat{}
+	}
+	for _, style := range setting.LinkStyle {
+		links, err := query.ExecuteQuery(&query.Query{Links: query.Links{Filters: style.Filters}}, config)
+		if err != nil {
+			return "", fmt.Errorf("error executing link style query: %v", err)
+		}
+		for _, link := range links.Links {
+			linkFormats[link.ID].merge(style.Format)
+		}
+	}
+
+	// Output the links sorted by their source and target.
+	links := diagram.SortLinks(config.Links)
+	if len(links) > 0 {
+		dot.WriteString("\n")
+		dot.WriteString("    // Links\n")
+	}
+	for _, link := range links {
+		attributes := linkFormats[link.ID].attributes()
+		attributes["label"] = link.Type
+
+		// Links to a cluster are drawn to a node inside of it and clipped at the cluster border
+		source := link.Source
+		if c := tree.Container(source); c != nil {
+			source = anchor(c)
+			attributes["ltail"] = "cluster_" + link.Source
+		}
+		target := link.Target
+		if c := tree.Container(target); c != nil {
+			target = anchor(c)
+			attributes["lhead"] = "cluster_" + link.Target
+		}
+
+		dot.WriteString(fmt.Sprintf("    %s;\n", formatElement(quote(source)+" -> "+quote(target), attributes, linkAttributeOrder)))
+	}
+
+	dot.WriteString("}\n")
+
+	return dot.String(), nil
+}
+
+// nodeAttributeOrder is the order the node attributes are written in
+var nodeAttributeOrder = []string{"label", "shape", "style", "color", "fillcolor", "fontcolor", "fontsize", "penwidth"}
+
+// linkAttributeOrder is the order the link attributes are written in
+var linkAttributeOrder = []string{"label", "style", "color", "penwidth", "arrowhead", "ltail", "lhead"}
+
+// formatElement returns the DOT statement for a node or link with the attributes that are set.
+func formatElement(element string, attributes map[string]string, order []string) string {
+	props := []string{}
+	for _, name := range order {
+		if value := attributes[name]; value != "" {
+			props = append(props, fmt.Sprintf("%s=%s", name, quote(value)))
+		}
+	}
+	if len(props) == 0 {
+		return element
+	}
+	return fmt.Sprintf("%s [%s]", element, strings.Join(props, ", "))
+}
+
+// quote returns the value as a quoted DOT ID, escaping the quotes and backslashes.
+func quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
+
+// anchor returns the first node rendered inside of the cluster, used as the
+// endpoint for links that connect to the cluster.
+func anchor(c *diagram.Container) string {
+	if len(c.Nodes) > 0 {
+		return c.Nodes[0]
+	}
+	return anchor(c.Containers[0])
+}
+
+// merge sets the attributes of the other format that are set.
+func (f *NodeStyleFormat) merge(other NodeStyleFormat) {
+	diagram.SetIfNotEmpty(&f.Shape, other.Shape)
+	diagram.SetIfNotEmpty(&f.Style, other.Style)
+	diagram.SetIfNotEmpty(&f.Color, other.Color)
+	diagram.SetIfNotEmpty(&f.FillColor, other.FillColor)
+	diagram.SetIfNotEmpty(&f.FontColor, other.FontColor)
+	diagram.SetIfNotEmpty(&f.FontSize, other.FontSize)
+	diagram.SetIfNotEmpty(&f.PenWidth, other.PenWidth)
+}
+
+// attributes returns the DOT attributes of the node by name, unset attributes are empty.
+func (f NodeStyleFormat) attributes() map[string]string {
+	return map[string]string{
+		"shape":     f.Shape,
+		"style":     f.Style,
+		"color":     f.Color,
+		"fillcolor": f.FillColor,
+		"fontcolor": f.FontColor,
+		"fontsize":  f.FontSize,
+		"penwidth":  f.PenWidth,
+	}
+}
+
+// merge sets the attributes of the other format that are set.
+func (l *LinkStyleFormat) merge(other LinkStyleFormat) {
+	diagram.SetIfNotEmpty(&l.Style, other.Style)
+	diagram.SetIfNotEmpty(&l.Color, other.Color)
+	diagram.SetIfNotEmpty(&l.PenWidth, other.PenWidth)
+	diagram.SetIfNotEmpty(&l.ArrowHead, other.ArrowHead)
+}
+
+// attributes returns the DOT attributes of the link by name, unset attributes are empty.
+func (l LinkStyleFormat) attributes() map[string]string {
+	return map[string]string{
+		"style":     l.Style,
+		"color":     l.Color,
+		"penwidth":  l.PenWidth,
+		"arrowhead": l.ArrowHead,
+	}
+}
@@ -0,0 +1,111 @@
+package dot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+)
+
+func TestGenerateDot(t *testing.T) {
+	err := filepath.Walk("../../tests", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			configPath := filepath.Join(path, "config.yaml")
+			dotConfigPath := filepath.Join(path, "dot.yaml")
+			dotPath := filepath.Join(path, "dot.gv")
+
+			if _, err := os.Stat(configPath); os.IsNotExist(err) {
+				return nil
+			}
+			if _, err := os.Stat(dotConfigPath); os.IsNotExist(err) {
+				return nil
+			}
+			if _, err := os.Stat(dotPath); os.IsNotExist(err) {
+				return nil
+			}
+
+			relDir, err := filepath.Rel("../../tests", path)
+			if err != nil {
+				return err
+			}
+
+			sanitizedRelDir := strings.ReplaceAll(relDir, string(filepath.Separator), "#")
+
+			t.Run(sanitizedRelDir, func(t *testing.T) {
+				config, err := configuration.LoadConfig(configPath)
+				if err != nil {
+					t.Fatalf("Failed to load config: %v", err)
+				}
+
+				dotConfig, err := LoadDot(dotConfigPath)
+				if err != nil {
+					t.Fatalf("Failed to load dot config: %v", err)
+				}
+
+				err = dotConfig.Validate()
+				if err != nil {
+					t.Fatalf("Dot config validation failed: %v", err)
+				}
+
+				expectedBytes, err := os.ReadFile(dotPath)
+				if err != nil {
+					t.Fatalf("Failed to read DOT file: %v", err)
+				}
+				expectedOutput := string(expectedBytes)
+
+				output, err := GenerateDot(config, dotConfig)
+				if err != nil {
+					t.Fatalf("GenerateDot returned error: %v", err)
+				}
+				if output != expectedOutput {
+					t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
+				}
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error walking through example folder: %v", err)
+	}
+}
+
+func TestGenerateDotClusterLinks(t *testing.T) {
+	config, err := configuration.ParseYAML(`
+nodes:
+  - id: platform
+    type: System
+  - id: api
+    type: Service
+    parent: platform
+  - id: client
+    type: Client
+links:
+  - source: client
+    target: platform
+    type: HTTPS
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	setting, err := ParseYAML("")
+	if err != nil {
+		t.Fatalf("Failed to parse settings: %v", err)
+	}
+
+	output, err := GenerateDot(config, setting)
+	if err != nil {
+		t.Fatalf("GenerateDot returned error: %v", err)
+	}
+
+	// The link to the cluster is drawn to the node inside of it and clipped at the cluster
+	expected := `"client" -> "api" [label="HTTPS", lhead="cluster_platform"];`
+	if !strings.Contains(output, expected) {
+		t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+	}
+}
@@ -0,0 +1,38 @@
+package dot
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseYAML parses the YAML content into a Dot
+func ParseYAML(content string) (*Dot, error) {
+	var config Dot
+	err := yaml.Unmarshal([]byte(content), &config)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling YAML: %v", err)
+	}
+
+	// Specify the default values if they were not provided
+
+	if config.Direction == "" {
+		config.Direction = "TB"
+	}
+
+	return &config, nil
+}
+
+// LoadDot loads and parses a single YAML Graphviz setting file from the given path.
+func LoadDot(filePath string) (*Dot, error) {
+
+	// Read the file contents to a string
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %v", err)
+	}
+
+	// Parse the YAML
+	return ParseYAML(string(data))
+}
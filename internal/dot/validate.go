@@ -0,0 +1,152 @@
+package dot
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/common"
+	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
+)
+
+// nodeShapes are the Graphviz node shapes that can be used in a node style
+var nodeShapes = []string{
+	"box", "rect", "rectangle", "square", "ellipse", "oval", "circle", "doublecircle",
+	"diamond", "cylinder", "component", "folder", "tab", "note", "box3d", "hexagon",
+	"octagon", "parallelogram", "trapezium", "house", "invhouse", "cds", "plain", "plaintext",
+}
+
+// nodeStyles are the Graphviz node styles that can be combined in a node style
+var nodeStyles = []string{"solid", "dashed", "dotted", "bold", "rounded", "filled", "striped", "diagonals", "invis"}
+
+// linkStyles are the Graphviz edge styles that can be used in a link style
+var linkStyles = []string{"solid", "dashed", "dotted", "bold", "invis"}
+
+// arrowHeads are the Graphviz arrow shapes that can be used in a link style
+var arrowHeads = []string{"normal", "inv", "dot", "odot", "invdot", "none", "tee", "empty", "diamond", "odiamond", "box", "obox", "open", "halfopen", "vee", "crow"}
+
+// Validate checks if the dot settings are valid, returning all of the problems
+// found as common.ValidationErrors.
+func (d *Dot) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the direction is valid
+	switch d.Direction {
+	case "TB":
+	case "BT":
+	case "LR":
+	case "RL":
+	default:
+		errs.Add("setting", "direction", fmt.Errorf("invalid direction: %s", d.Direction))
+	}
+
+	// Validate the node label is valid
+	if d.NodeLabel != "" {
+		// Perform same validation as attribute values
+		errs.Add("setting", "nodeLabel", common.IsValidValue(d.NodeLabel, "nodeLabel"))
+	}
+
+	// Validate the cluster nodes are valid
+	errs.Merge("condition", "clusterNodes", d.ClusterNodes.Validate())
+
+	// Validate all of the node styles
+	for i := range d.NodeStyle {
+		errs.Merge("nodeStyle", fmt.Sprintf("nodeStyles[%d]", i), d.NodeStyle[i].Validate())
+	}
+
+	// Validate all of the link styles
+	for i := range d.LinkStyle {
+		errs.Merge("linkStyle", fmt.Sprintf("linkStyles[%d]", i), d.LinkStyle[i].Validate())
+	}
+
+	return errs.ErrOrNil()
+}
+
+func (n *NodeStyle) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the filters are valid
+	for i := range n.Filters {
+		errs.Merge("condition", fmt.Sprintf("filters[%d]", i), n.Filters[i].Validate(query.NodeCondition))
+	}
+
+	// Validate the format is valid
+	errs.Merge("nodeStyle", "format", n.Format.Validate())
+
+	return errs.ErrOrNil()
+}
+
+// Validate checks if the node style format is valid, returning the problems
+// with every field as common.ValidationErrors.
+func (n *NodeStyleFormat) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the shape is valid
+	errs.Add("nodeStyle", "shape", isValidOption("shape", n.Shape, nodeShapes))
+
+	// Multiple styles can be combined with a comma such as 'rounded,filled'
+	if n.Style != "" {
+		for _, style := range strings.Split(n.Style, ",") {
+			errs.Add("nodeStyle", "style", isValidOption("style", strings.TrimSpace(style), nodeStyles))
+		}
+	}
+
+	// Validate the colors are valid
+	errs.Add("nodeStyle", "color", common.IsValidColor("color", n.Color))
+	errs.Add("nodeStyle", "fillcolor", common.IsValidColor("fillcolor", n.FillColor))
+	errs.Add("nodeStyle", "fontcolor", common.IsValidColor("fontcolor", n.FontColor))
+
+	// Validate the sizes are valid numbers
+	errs.Add("nodeStyle", "fontsize", common.IsValidNumber("fontsize", n.FontSize))
+	errs.Add("nodeStyle", "penwidth", common.IsValidNumber("penwidth", n.PenWidth))
+
+	// Ensure at least one attribute is set
+	if *n == (NodeStyleFormat{}) {
+		errs.Add("nodeStyle", "", fmt.Errorf("at least one 'format' attribute must be set"))
+	}
+
+	return errs.ErrOrNil()
+}
+
+func (l *LinkStyle) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the filters are valid
+	for i := range l.Filters {
+		errs.Merge("condition", fmt.Sprintf("filters[%d]", i), l.Filters[i].Validate(query.LinkCondition))
+	}
+
+	// Validate the format is valid
+	errs.Merge("linkStyle", "format", l.Format.Validate())
+
+	return errs.ErrOrNil()
+}
+
+// Validate checks if the link style format is valid, returning the problems
+// with every field as common.ValidationErrors.
+func (l *LinkStyleFormat) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the style and arrow head are valid
+	errs.Add("linkStyle", "style", isValidOption("style", l.Style, linkStyles))
+	errs.Add("linkStyle", "arrowhead", isValidOption("arrowhead", l.ArrowHead, arrowHeads))
+
+	// Validate the color and width are valid
+	errs.Add("linkStyle", "color", common.IsValidColor("color", l.Color))
+	errs.Add("linkStyle", "penwidth", common.IsValidNumber("penwidth", l.PenWidth))
+
+	// Ensure at least one attribute is set
+	if *l == (LinkStyleFormat{}) {
+		errs.Add("linkStyle", "", fmt.Errorf("at least one 'format' attribute must be set"))
+	}
+
+	return errs.ErrOrNil()
+}
+
+// isValidOption checks the value is one of the allowed options if it is set.
+func isValidOption(field string, value string, options []string) error {
+	if value != "" && !slices.Contains(options, value) {
+		return fmt.Errorf("invalid %s: '%s'", field, value)
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
+)
+
+// Violation is a node or link that does not satisfy a rule.
+type Violation struct {
+	// The name of the rule that was violated
+	Rule string
+	// The severity of the rule that was violated
+	Severity string
+	// The message of the rule that was violated
+	Message string
+	// The node or link that violates the rule, such as "node 'api'"
+	Element string
+	// The details of the failed check, empty if the element was forbidden
+	Detail string
+	// Where the node or link was defined
+	Origin configuration.Origin
+}
+
+// String returns the violation as a single line.
+func (v Violation) String() string {
+	line := fmt.Sprintf("%s: [%s] %s: %s", v.Severity, v.Rule, v.Element, v.Message)
+	if v.Detail != "" {
+		line += fmt.Sprintf(" (%s)", v.Detail)
+	}
+	if location := v.Origin.String(); location != "" {
+		line += fmt.Sprintf(" (at %s)", location)
+	}
+	return line
+}
+
+// Result holds the violations found by evaluating the rules.
+type Result struct {
+	Violations []Violation
+}
+
+// Count returns the number of violations with the given severity.
+func (r *Result) Count(severity string) int {
+	count := 0
+	for _, violation := range r.Violations {
+		if violation.Severity == severity {
+			count++
+		}
+	}
+	return count
+}
+
+// HasErrors returns true if any of the violations has the error severity.
+func (r *Result) HasErrors() bool {
+	return r.Count(SeverityError) > 0
+}
+
+// String returns the violations one per line followed by a summary.
+func (r *Result) String() string {
+	var output strings.Builder
+	for _, violation := range r.Violations {
+		output.WriteString(violation.String())
+		output.WriteString("\n")
+	}
+	if len(r.Violations) > 0 {
+		output.WriteString("\n")
+	}
+	output.WriteString(fmt.Sprintf("%s, %s, %s",
+		plural(r.Count(SeverityError), "error", "errors"),
+		plural(r.Count(SeverityWarning), "warning", "warnings"),
+		plural(r.Count(SeverityInfo), "info", "info")))
+	return output.String()
+}
+
+// Evaluate runs the rules against the configuration returning the violations
+// in the order of the rules and then the order of the nodes and links.
+func Evaluate(lint *Lint, config *configuration.Config) (*Result, error) {
+	ctx := query.NewConfigContext(config)
+	result := &Result{Violations: []Violation{}}
+
+	for _, rule := range lint.Rules {
+		var violations []Violation
+		var err error
+		if rule.Links != nil {
+			violations, err = evaluateLinkRule(rule, config)
+		} else {
+			violations, err = evaluateNodeRule(rule, config, ctx)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error evaluating rule '%s': %w", rule.Name, err)
+		}
+		result.Violations = append(result.Violations, violations...)
+	}
+
+	return result, nil
+}
+
+// evaluateLinkRule reports every link selected by the rule.
+func evaluateLinkRule(rule Rule, config *configuration.Config) ([]Violation, error) {
+	selected, err := query.ExecuteQuery(&query.Query{Links: *rule.Links}, config)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := []Violation{}
+	for _, link := range selected.Links {
+		selector := configuration.LinkSelector{Source: link.Source, Target: link.Target, Type: link.Type}
+		violations = append(violations, newViolation(rule, fmt.Sprintf("link '%s'", selector), "", link.Origin))
+	}
+	return violations, nil
+}
+
+// evaluateNodeRule reports the nodes selected by the rule, or the selected
+// nodes that fail the checks if the rule has an assert.
+func evaluateNodeRule(rule Rule, config *configuration.Config, ctx *query.ConfigContext) ([]Violation, error) {
+	selectedNodes := config.Nodes
+	if rule.Nodes != nil {
+		selected, err := query.ExecuteQuery(&query.Query{Nodes: *rule.Nodes}, config)
+		if err != nil {
+			return nil, err
+		}
+		selectedNodes = selected.Nodes
+	}
+
+	violations := []Violation{}
+	if rule.Assert == nil {
+		for _, node := range selectedNodes {
+			violations = append(violations, newViolation(rule, fmt.Sprintf("node '%s'", node.ID), "", node.Origin))
+		}
+		return violations, nil
+	}
+
+	// Determine the nodes that match the assert filters
+	matching := make(map[string]bool)
+	if len(rule.Assert.Filters) > 0 {
+		matched, err := query.ExecuteQuery(&query.Query{Nodes: query.Nodes{Filters: rule.Assert.Filters}}, config)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range matched.Nodes {
+			matching[node.ID] = true
+		}
+	}
+
+	for _, node := range selectedNodes {
+		element := fmt.Sprintf("node '%s'", node.ID)
+
+		if len(rule.Assert.Filters) > 0 && !matching[node.ID] {
+			violations = append(violations, newViolation(rule, element, "does not match the assert filters", node.Origin))
+		}
+
+		checks := []struct {
+			count *Count
+			value int
+			noun  string
+		}{
+			{rule.Assert.InboundLinks, countLinks(ctx.InboundLinks[node.ID], rule.Assert.InboundLinks), "inbound"},
+			{rule.Assert.OutboundLinks, countLinks(ctx.OutboundLinks[node.ID], rule.Assert.OutboundLinks), "outbound"},
+			{rule.Assert.Children, len(ctx.ChildrenMap[node.ID]), "children"},
+			{rule.Assert.Depth, ctx.Depth(node.ID), "depth"},
+		}
+		for _, check := range checks {
+			if check.count == nil || check.count.allows(check.value) {
+				continue
+			}
+			detail := fmt.Sprintf("found %s, expected %s", check.count.describe(check.value, check.noun), check.count.expected())
+			violations = append(violations, newViolation(rule, element, detail, node.Origin))
+		}
+	}
+
+	return violations, nil
+}
+
+// newViolation creates the violation of the rule for the node or link.
+func newViolation(rule Rule, element string, detail string, origin configuration.Origin) Violation {
+	return Violation{
+		Rule:     rule.Name,
+		Severity: rule.Severity,
+		Message:  rule.Message,
+		Element:  element,
+		Detail:   detail,
+		Origin:   origin,
+	}
+}
+
+// countLinks counts the links of the types the count applies to.
+func countLinks(links []*configuration.Link, count *Count) int {
+	if count == nil {
+		return 0
+	}
+	total := 0
+	for _, link := range links {
+		if len(count.LinkTypes) == 0 || slices.Contains(count.LinkTypes, link.Type) {
+			total++
+		}
+	}
+	return total
+}
+
+// allows returns true if the value is within the count.
+func (c *Count) allows(value int) bool {
+	if c.Equals != nil && value != *c.Equals {
+		return false
+	}
+	if c.Min != nil && value < *c.Min {
+		return false
+	}
+	if c.Max != nil && value > *c.Max {
+		return false
+	}
+	return true
+}
+
+// expected describes the values allowed by the count.
+func (c *Count) expected() string {
+	switch {
+	case c.Equals != nil:
+		return fmt.Sprintf("exactly %d", *c.Equals)
+	case c.Min != nil && c.Max != nil:
+		return fmt.Sprintf("between %d and %d", *c.Min, *c.Max)
+	case c.Min != nil:
+		return fmt.Sprintf("at least %d", *c.Min)
+	default:
+		return fmt.Sprintf("at most %d", *c.Max)
+	}
+}
+
+// describe describes the counted value, such as "2 inbound links" or "depth 6".
+func (c *Count) describe(value int, noun string) string {
+	switch noun {
+	case "depth":
+		return fmt.Sprintf("depth %d", value)
+	case "children":
+		return plural(value, "child", "children")
+	}
+
+	linkNoun := noun
+	if len(c.LinkTypes) > 0 {
+		linkNoun += " " + strings.Join(c.LinkTypes, " or ")
+	}
+	return plural(value, linkNoun+" link", linkNoun+" links")
+}
+
+// plural formats the count with the singular or plural form of the noun.
+func plural(count int, singular string, pluralForm string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, singular)
+	}
+	return fmt.Sprintf("%d %s", count, pluralForm)
+}
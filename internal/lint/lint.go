@@ -0,0 +1,66 @@
+package lint
+
+import (
+	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
+)
+
+const (
+	// SeverityError marks a violation that fails the lint
+	SeverityError = "error"
+	// SeverityWarning marks a violation that is reported but does not fail the lint
+	SeverityWarning = "warning"
+	// SeverityInfo marks a violation that is informational only
+	SeverityInfo = "info"
+)
+
+// Lint contains the architecture policy rules to evaluate against a configuration.
+type Lint struct {
+	// The rules to evaluate in order
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule is a single architecture policy.
+//
+// A rule selects either nodes or links using the query filters. Without an assert
+// every selected node or link is a violation, with an assert every selected node
+// that does not satisfy it is a violation.
+type Rule struct {
+	// The name of the rule used to identify it in the violations
+	Name string `yaml:"name"`
+	// The severity of the violations (error, warning, info)
+	Severity string `yaml:"severity,omitempty"`
+	// The message describing the violation
+	Message string `yaml:"message"`
+	// The query to identify the nodes the rule applies to, all nodes if not set
+	Nodes *query.Nodes `yaml:"nodes,omitempty"`
+	// The query to identify the links the rule applies to
+	Links *query.Links `yaml:"links,omitempty"`
+	// The checks every selected node must satisfy
+	Assert *Assert `yaml:"assert,omitempty"`
+}
+
+// Assert contains the checks a node must satisfy, all of the checks that are set must pass.
+type Assert struct {
+	// The filters the node must match
+	Filters []query.Filter `yaml:"filters,omitempty"`
+	// The number of links that target the node
+	InboundLinks *Count `yaml:"inboundLinks,omitempty"`
+	// The number of links that originate from the node
+	OutboundLinks *Count `yaml:"outboundLinks,omitempty"`
+	// The number of direct children of the node
+	Children *Count `yaml:"children,omitempty"`
+	// The depth of the node in the parent hierarchy, top-level nodes have a depth of 1
+	Depth *Count `yaml:"depth,omitempty"`
+}
+
+// Count is the allowed range for a counted value.
+type Count struct {
+	// The exact value required
+	Equals *int `yaml:"equals,omitempty"`
+	// The minimum value allowed
+	Min *int `yaml:"min,omitempty"`
+	// The maximum value allowed
+	Max *int `yaml:"max,omitempty"`
+	// The link types to count, if empty all link types are counted
+	LinkTypes []string `yaml:"linkTypes,omitempty"`
+}
@@ -0,0 +1,94 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+)
+
+func TestEvaluateLint(t *testing.T) {
+	err := filepath.Walk("../../tests", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			configPath := filepath.Join(path, "config.yaml")
+			lintConfigPath := filepath.Join(path, "lint.yaml")
+			lintPath := filepath.Join(path, "lint.txt")
+
+			if _, err := os.Stat(configPath); os.IsNotExist(err) {
+				return nil
+			}
+			if _, err := os.Stat(lintConfigPath); os.IsNotExist(err) {
+				return nil
+			}
+			if _, err := os.Stat(lintPath); os.IsNotExist(err) {
+				return nil
+			}
+
+			relDir, err := filepath.Rel("../../tests", path)
+			if err != nil {
+				return err
+			}
+
+			sanitizedRelDir := strings.ReplaceAll(relDir, string(filepath.Separator), "#")
+
+			t.Run(sanitizedRelDir, func(t *testing.T) {
+				// Load the config the same way as the CLI so the locations cite the config file
+				content, err := os.ReadFile(configPath)
+				if err != nil {
+					t.Fatalf("Failed to read config: %v", err)
+				}
+
+				config, err := configuration.ParseYAML(string(content))
+				if err != nil {
+					t.Fatalf("Failed to parse config: %v", err)
+				}
+
+				err = config.ResolveImports(configPath)
+				if err != nil {
+					t.Fatalf("Failed to resolve imports: %v", err)
+				}
+
+				err = config.Validate()
+				if err != nil {
+					t.Fatalf("Failed to validate config: %v", err)
+				}
+
+				lint, err := LoadLint(lintConfigPath)
+				if err != nil {
+					t.Fatalf("Failed to load lint: %v", err)
+				}
+
+				err = lint.Validate()
+				if err != nil {
+					t.Fatalf("Lint validation failed: %v", err)
+				}
+
+				expectedBytes, err := os.ReadFile(lintPath)
+				if err != nil {
+					t.Fatalf("Failed to read lint output: %v", err)
+				}
+				expectedOutput := string(expectedBytes)
+
+				result, err := Evaluate(lint, config)
+				if err != nil {
+					t.Fatalf("Evaluate returned error: %v", err)
+				}
+
+				// The CLI is run from the root of the repository
+				output := strings.ReplaceAll(result.String(), "../../", "")
+				if output != expectedOutput {
+					t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
+				}
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error walking through example folder: %v", err)
+	}
+}
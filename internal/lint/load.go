@@ -0,0 +1,40 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseYAML parses the YAML content into a Lint
+func ParseYAML(content string) (*Lint, error) {
+	var lint Lint
+	err := yaml.Unmarshal([]byte(content), &lint)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling YAML: %v", err)
+	}
+
+	// Specify the default values if they were not provided
+
+	for i := range lint.Rules {
+		if lint.Rules[i].Severity == "" {
+			lint.Rules[i].Severity = SeverityError
+		}
+	}
+
+	return &lint, nil
+}
+
+// LoadLint loads and parses a single YAML lint rules file from the given path.
+func LoadLint(filePath string) (*Lint, error) {
+
+	// Read the file contents to a string
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %v", err)
+	}
+
+	// Parse the YAML
+	return ParseYAML(string(data))
+}
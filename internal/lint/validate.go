@@ -0,0 +1,135 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/common"
+	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
+)
+
+// Validate checks if the lint rules are valid, returning all of the problems
+// found as common.ValidationErrors.
+func (l *Lint) Validate() error {
+	var errs common.ValidationErrors
+
+	if len(l.Rules) == 0 {
+		errs.Add("rule", "rules", errors.New("at least one rule is required"))
+	}
+
+	// Validate each of the rules, the names must be unique to identify the violations
+	names := make(map[string]bool)
+	for i := range l.Rules {
+		path := fmt.Sprintf("rules[%d]", i)
+		rule := &l.Rules[i]
+
+		if rule.Name != "" && names[rule.Name] {
+			errs.Add("rule", path+".name", fmt.Errorf("duplicate rule name found: '%s'", rule.Name))
+		}
+		names[rule.Name] = true
+
+		errs.Merge("rule", path, rule.Validate())
+	}
+
+	return errs.ErrOrNil()
+}
+
+// Validate checks if the rule is valid.
+func (r *Rule) Validate() error {
+	var errs common.ValidationErrors
+
+	errs.Add("rule", "name", common.IsValidName(r.Name, "name"))
+
+	switch r.Severity {
+	case SeverityError:
+	case SeverityWarning:
+	case SeverityInfo:
+	default:
+		errs.Add("rule", "severity", fmt.Errorf("invalid severity: '%s'", r.Severity))
+	}
+
+	errs.Add("rule", "message", common.IsValidValue(r.Message, "message"))
+
+	// A rule applies to either the nodes or the links
+	if r.Links != nil {
+		if r.Nodes != nil || r.Assert != nil {
+			errs.Add("rule", "links", fmt.Errorf("rule '%s' cannot combine 'links' with 'nodes' or 'assert'", r.Name))
+		}
+		for i := range r.Links.Filters {
+			errs.Merge("condition", fmt.Sprintf("links.filters[%d]", i), r.Links.Filters[i].Validate(query.LinkCondition))
+		}
+	} else if r.Nodes == nil && r.Assert == nil {
+		errs.Add("rule", "", fmt.Errorf("rule '%s' must define 'nodes', 'links' or 'assert'", r.Name))
+	}
+
+	if r.Nodes != nil {
+		errs.Merge("condition", "nodes", r.Nodes.Validate())
+	}
+
+	if r.Assert != nil {
+		errs.Merge("assert", "assert", r.Assert.validate())
+	}
+
+	return errs.ErrOrNil()
+}
+
+// validate checks if the assert is valid.
+func (a *Assert) validate() error {
+	var errs common.ValidationErrors
+
+	if len(a.Filters) == 0 && a.InboundLinks == nil && a.OutboundLinks == nil && a.Children == nil && a.Depth == nil {
+		errs.Add("assert", "", errors.New("'assert' must define at least one check"))
+	}
+
+	for i := range a.Filters {
+		errs.Merge("condition", fmt.Sprintf("filters[%d]", i), a.Filters[i].Validate(query.NodeCondition))
+	}
+
+	errs.Merge("assert", "inboundLinks", a.InboundLinks.validate("inboundLinks", true))
+	errs.Merge("assert", "outboundLinks", a.OutboundLinks.validate("outboundLinks", true))
+	errs.Merge("assert", "children", a.Children.validate("children", false))
+	errs.Merge("assert", "depth", a.Depth.validate("depth", false))
+
+	return errs.ErrOrNil()
+}
+
+// validate checks if the count is valid, a nil count is not checked.
+func (c *Count) validate(field string, allowLinkTypes bool) error {
+	if c == nil {
+		return nil
+	}
+
+	var errs common.ValidationErrors
+
+	if c.Equals == nil && c.Min == nil && c.Max == nil {
+		errs.Add("assert", "", fmt.Errorf("'%s' must define 'equals', 'min' or 'max'", field))
+	}
+
+	if c.Equals != nil && (c.Min != nil || c.Max != nil) {
+		errs.Add("assert", "equals", fmt.Errorf("'%s.equals' cannot be combined with 'min' or 'max'", field))
+	}
+
+	bounds := []struct {
+		name  string
+		value *int
+	}{{"equals", c.Equals}, {"min", c.Min}, {"max", c.Max}}
+	for _, bound := range bounds {
+		if bound.value != nil && *bound.value < 0 {
+			errs.Add("assert", bound.name, fmt.Errorf("'%s.%s' cannot be negative", field, bound.name))
+		}
+	}
+
+	if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+		errs.Add("assert", "min", fmt.Errorf("'%s.min' cannot be greater than '%s.max'", field, field))
+	}
+
+	if len(c.LinkTypes) > 0 && !allowLinkTypes {
+		errs.Add("assert", "linkTypes", fmt.Errorf("'%s.linkTypes' is only allowed for link counts", field))
+	}
+
+	for _, linkType := range c.LinkTypes {
+		errs.Add("assert", "linkTypes", common.IsValidName(linkType, field+".linkTypes"))
+	}
+
+	return errs.ErrOrNil()
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/UnitVectorY-Labs/YAMLtecture/internal/common"
 	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/diagram"
 	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
 )
 
@@ -69,6 +70,11 @@ type subgraphContainer struct {
 func GenerateMermaid(config *configuration.Config, setting *Mermaid) (string, error) {
 	var mermaid strings.Builder
 
+	// Namespaced IDs are rewritten for Mermaid and cannot collide with another ID.
+	if err := diagram.CheckIDs(config, mermaidID); err != nil {
+		return "", err
+	}
+
 	// Write the header.
 	mermaid.WriteString(fmt.Sprintf("flowchart %s\n", setting.Direction))
 
@@ -92,7 +98,7 @@ func GenerateMermaid(config *configuration.Config, setting *Mermaid) (string, er
 			}
 
 			for _, node := range nodes.Nodes {
-				styleMap[styleClassName] = append(styleMap[styleClassName], node.ID)
+				styleMap[styleClassName] = append(styleMap[styleClassName], mermaidID(node.ID))
 			}
 
 			mermaid.WriteString(style.Format.print(styleClassName))
@@ -102,6 +108,20 @@ func GenerateMermaid(config *configuration.Config, setting *Mermaid) (string, er
 		mermaid.WriteString("\n")
 	}
 
+	// Write the style for external stub nodes added at the boundary of a query.
+	externalNodes := []string{}
+	for _, node := range config.Nodes {
+		if node.External {
+			externalNodes = append(externalNodes, mermaidID(node.ID))
+		}
+	}
+	sort.Strings(externalNodes)
+	if len(externalNodes) > 0 {
+		mermaid.WriteString("    %% External Nodes\n")
+		mermaid.WriteString("    classDef external stroke-dasharray:5 5;\n")
+		mermaid.WriteString("\n")
+	}
+
 	mermaid.WriteString("    %% Nodes\n")
 
 	// Build a lookup for nodes and a parent map.
@@ -123,7 +143,10 @@ func GenerateMermaid(config *configuration.Config, setting *Mermaid) (string, er
 			return "", fmt.Errorf("error executing subgraph query: %v", err)
 		}
 		for _, node := range subgraphConfig.Nodes {
-			explicit[node.ID] = true
+			// External stub nodes are always rendered as nodes
+			if !node.External {
+				explicit[node.ID] = true
+			}
 		}
 	}
 
@@ -133,7 +156,7 @@ func GenerateMermaid(config *configuration.Config, setting *Mermaid) (string, er
 		label := ""
 		if setting.NodeLabel != "" {
 			if node, ok := nodeLookup[id]; ok {
-				if val, ok := node.Attributes[setting.NodeLabel].(string); ok && val != "" {
+				if val := configuration.NodeAttributeLabel(node, setting.NodeLabel); val != "" {
 					label = common.SanitizeLabel(val)
 				}
 			}
@@ -205,21 +228,23 @@ func GenerateMermaid(config *configuration.Config, setting *Mermaid) (string, er
 		subgraphlabel := ""
 		if cont.Label != "" {
 			subgraphlabel = fmt.Sprintf("[%s]", cont.Label)
+		} else if mermaidID(cont.ID) != cont.ID {
+			subgraphlabel = fmt.Sprintf("[%s]", cont.ID)
 		}
 
-		mermaid.WriteString(fmt.Sprintf("%ssubgraph %s%s\n", indent, cont.ID, subgraphlabel))
+		mermaid.WriteString(fmt.Sprintf("%ssubgraph %s%s\n", indent, mermaidID(cont.ID), subgraphlabel))
 
 		// Output contained non-explicit nodes.
 		sort.Strings(cont.Nodes)
 		for _, nid := range cont.Nodes {
 			node := nodeLookup[nid]
 			if setting.NodeLabel != "" {
-				if val, ok := node.Attributes[setting.NodeLabel].(string); ok && val != "" {
-					mermaid.WriteString(fmt.Sprintf("%s    %s[%s]\n", indent, nid, common.SanitizeLabel(val)))
+				if val := configuration.NodeAttributeLabel(node, setting.NodeLabel); val != "" {
+					mermaid.WriteString(fmt.Sprintf("%s    %s[%s]\n", indent, mermaidID(nid), common.SanitizeLabel(val)))
 					continue
 				}
 			}
-			mermaid.WriteString(fmt.Sprintf("%s    %s\n", indent, nid))
+			mermaid.WriteString(fmt.Sprintf("%s    %s\n", indent, plainNode(nid)))
 		}
 		// Output nested explicit containers.
 		sort.Slice(cont.Subgraphs, func(i, j int) bool {
@@ -239,12 +264,12 @@ func GenerateMermaid(config *configuration.Config, setting *Mermaid) (string, er
 	for _, nid := range topLevelNodes {
 		node := nodeLookup[nid]
 		if setting.NodeLabel != "" {
-			if val, ok := node.Attributes[setting.NodeLabel].(string); ok && val != "" {
-				mermaid.WriteString(fmt.Sprintf("    %s[%s]\n", nid, common.SanitizeLabel(val)))
+			if val := configuration.NodeAttributeLabel(node, setting.NodeLabel); val != "" {
+				mermaid.WriteString(fmt.Sprintf("    %s[%s]\n", mermaidID(nid), common.SanitizeLabel(val)))
 				continue
 			}
 		}
-		mermaid.WriteString(fmt.Sprintf("    %s\n", nid))
+		mermaid.WriteString(fmt.Sprintf("    %s\n", plainNode(nid)))
 	}
 
 	// Output the classes to format the nodes
@@ -270,19 +295,21 @@ func GenerateMermaid(config *configuration.Config, setting *Mermaid) (string, er
 		}
 	}
 
+	// Output the class to format the external nodes
+	if len(externalNodes) > 0 {
+		mermaid.WriteString("\n")
+		mermaid.WriteString("    %% External Nodes\n")
+		mermaid.WriteString(fmt.Sprintf("    class %s external\n", strings.Join(externalNodes, ",")))
+	}
+
 	// Output the links.
 	mermaid.WriteString("\n")
 	mermaid.WriteString("    %% Links\n")
-	sort.Slice(config.Links, func(i, j int) bool {
-		if config.Links[i].Source == config.Links[j].Source {
-			return config.Links[i].Target < config.Links[j].Target
-		}
-		return config.Links[i].Source < config.Links[j].Source
-	})
+	links := diagram.SortLinks(config.Links)
 
 	idMap := make(map[int]string)
-	for i, rel := range config.Links {
-		line := fmt.Sprintf("    %s -->|%s| %s\n", rel.Source, rel.Type, rel.Target)
+	for i, rel := range links {
+		line := fmt.Sprintf("    %s -->|%s| %s\n", mermaidID(rel.Source), rel.Type, mermaidID(rel.Target))
 		mermaid.WriteString(line)
 		idMap[i] = rel.ID
 	}
@@ -299,15 +326,15 @@ func GenerateMermaid(config *configuration.Config, setting *Mermaid) (string, er
 				},
 			}
 
-			links, err := query.ExecuteQuery(&syntheticQuery, config)
+			styled, err := query.ExecuteQuery(&syntheticQuery, config)
 			if err != nil {
 				return "", fmt.Errorf("error executing subgraph query: %v", err)
 			}
 
 			// Get the IDs of the links that need this style applied
 			linkIndices := []int{}
-			for j, link := range config.Links {
-				for _, l := range links.Links {
+			for j, link := range links {
+				for _, l := range styled.Links {
 					if l.ID == link.ID {
 						linkIndices = append(linkIndices, j)
 					}
@@ -323,6 +350,21 @@ func GenerateMermaid(config *configuration.Config, setting *Mermaid) (string, er
 	return mermaid.String(), nil
 }
 
+// mermaidID returns the node ID in a form Mermaid accepts, replacing the '/' in
+// namespaced IDs such as 'payments/api' with '__'.
+func mermaidID(id string) string {
+	return strings.ReplaceAll(id, configuration.NamespaceSeparator, "__")
+}
+
+// plainNode returns the Mermaid text for a node without a label attribute, using
+// the original ID as the label if it had to be changed to be a valid Mermaid ID.
+func plainNode(id string) string {
+	if escaped := mermaidID(id); escaped != id {
+		return fmt.Sprintf("%s[%s]", escaped, id)
+	}
+	return id
+}
+
 func (l LinkStyleFormat) print(indices []int) string {
 	var style strings.Builder
 
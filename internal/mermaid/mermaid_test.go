@@ -73,3 +73,27 @@ func TestGenerateMermaid(t *testing.T) {
 		t.Fatalf("Error walking through example folder: %v", err)
 	}
 }
+
+func TestGenerateMermaidIDCollision(t *testing.T) {
+	config, err := configuration.ParseYAML(`
+nodes:
+  - id: payments/api
+    type: Service
+  - id: payments__api
+    type: Service
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	setting, err := ParseYAML(`direction: TB`)
+	if err != nil {
+		t.Fatalf("Failed to parse settings: %v", err)
+	}
+
+	_, err = GenerateMermaid(config, setting)
+	expected := "node IDs 'payments/api' and 'payments__api' are both rendered as 'payments__api'"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error %q, got: %v", expected, err)
+	}
+}
@@ -7,8 +7,10 @@ import (
 	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
 )
 
-// Validate checks if the mermaid is valid.
+// Validate checks if the mermaid is valid, returning all of the problems
+// found as common.ValidationErrors.
 func (m *Mermaid) Validate() error {
+	var errs common.ValidationErrors
 
 	// Validate the direction is valid
 	switch m.Direction {
@@ -18,173 +20,98 @@ func (m *Mermaid) Validate() error {
 	case "RL":
 	case "LR":
 	default:
-		return fmt.Errorf("invalid direction: %s", m.Direction)
+		errs.Add("setting", "direction", fmt.Errorf("invalid direction: %s", m.Direction))
 	}
 
 	// Validate the node label is valid
 	if m.NodeLabel != "" {
 		// Perform same validation as attribute values
-		err := common.IsValidValue(m.NodeLabel, "nodeLabel")
-		if err != nil {
-			return err
-		}
+		errs.Add("setting", "nodeLabel", common.IsValidValue(m.NodeLabel, "nodeLabel"))
 	}
 
 	// Validate the subgraph nodes are valid
-	err := m.SubgraphNodes.Validate()
-	if err != nil {
-		return err
-	}
+	errs.Merge("condition", "subgraphNodes", m.SubgraphNodes.Validate())
 
 	// Validate all of the node styles
-	for _, nodeStyle := range m.NodeStyle {
-		err := nodeStyle.Validate()
-		if err != nil {
-			return err
-		}
+	for i := range m.NodeStyle {
+		errs.Merge("nodeStyle", fmt.Sprintf("nodeStyles[%d]", i), m.NodeStyle[i].Validate())
 	}
 
 	// Validate all of the link styles
-	for _, linkStyle := range m.LinkStyle {
-		err := linkStyle.Validate()
-		if err != nil {
-			return err
-		}
+	for i := range m.LinkStyle {
+		errs.Merge("linkStyle", fmt.Sprintf("linkStyles[%d]", i), m.LinkStyle[i].Validate())
 	}
 
-	return nil
+	return errs.ErrOrNil()
 }
 
 func (n *NodeStyle) Validate() error {
+	var errs common.ValidationErrors
 
 	// Validate the filters are valid
-	for _, filter := range n.Filters {
-		err := filter.Validate(query.NodeCondition)
-		if err != nil {
-			return err
-		}
+	for i := range n.Filters {
+		errs.Merge("condition", fmt.Sprintf("filters[%d]", i), n.Filters[i].Validate(query.NodeCondition))
 	}
 
 	// Validate the format is valid
-	err := n.Format.Validate()
-	if err != nil {
-		return err
-	}
+	errs.Merge("nodeStyle", "format", n.Format.Validate())
 
-	return nil
+	return errs.ErrOrNil()
 }
 
+// Validate checks if the node style format is valid, returning the problems
+// with every field as common.ValidationErrors.
 func (n *NodeStyleFormat) Validate() error {
+	var errs common.ValidationErrors
 
-	hasAttribute := false
-
-	// Validate the fill is valid
-	err := common.IsValidColor("fill", n.Fill)
-	if err != nil {
-		return err
-	} else if n.Fill != "" {
-		hasAttribute = true
-	}
-
-	// Validate the color is valid
-	err = common.IsValidColor("color", n.Color)
-	if err != nil {
-		return err
-	} else if n.Color != "" {
-		hasAttribute = true
-	}
-
-	// Validate the stroke width is valid integer suffixed with 'px'
-	err = common.IsValidPixel("stroke-width", n.StrokeWidth)
-	if err != nil {
-		return err
-	} else if n.StrokeWidth != "" {
-		hasAttribute = true
-	}
-
-	// Validate the font size is valid integer suffixed with 'px'
-	err = common.IsValidPixel("font-size", n.FontSize)
-	if err != nil {
-		return err
-	} else if n.FontSize != "" {
-		hasAttribute = true
-	}
-
-	// Validate the padding is valid integer suffixed with 'px'
-	err = common.IsValidPixel("padding", n.Padding)
-	if err != nil {
-		return err
-	} else if n.Padding != "" {
-		hasAttribute = true
-	}
-
-	// Validate the rx is valid integer suffixed with 'px'
-	err = common.IsValidPixel("rx", n.Rx)
-	if err != nil {
-		return err
-	} else if n.Rx != "" {
-		hasAttribute = true
-	}
+	// Validate the colors are valid
+	errs.Add("nodeStyle", "fill", common.IsValidColor("fill", n.Fill))
+	errs.Add("nodeStyle", "color", common.IsValidColor("color", n.Color))
 
-	// Validate the ry is valid integer suffixed with 'px'
-	err = common.IsValidPixel("ry", n.Ry)
-	if err != nil {
-		return err
-	} else if n.Ry != "" {
-		hasAttribute = true
-	}
+	// Validate the sizes are valid integers suffixed with 'px'
+	errs.Add("nodeStyle", "stroke-width", common.IsValidPixel("stroke-width", n.StrokeWidth))
+	errs.Add("nodeStyle", "font-size", common.IsValidPixel("font-size", n.FontSize))
+	errs.Add("nodeStyle", "padding", common.IsValidPixel("padding", n.Padding))
+	errs.Add("nodeStyle", "rx", common.IsValidPixel("rx", n.Rx))
+	errs.Add("nodeStyle", "ry", common.IsValidPixel("ry", n.Ry))
 
 	// Ensure at least one attribute is set
-	if !hasAttribute {
-		return fmt.Errorf("at least one 'format' attribute must be set")
+	if n.Fill == "" && n.Color == "" && n.StrokeWidth == "" && n.FontSize == "" && n.Padding == "" && n.Rx == "" && n.Ry == "" {
+		errs.Add("nodeStyle", "", fmt.Errorf("at least one 'format' attribute must be set"))
 	}
 
-	return nil
+	return errs.ErrOrNil()
 }
 
 func (l *LinkStyle) Validate() error {
+	var errs common.ValidationErrors
 
 	// Validate the filters are valid
-	for _, filter := range l.Filters {
-		err := filter.Validate(query.LinkCondition)
-		if err != nil {
-			return err
-		}
+	for i := range l.Filters {
+		errs.Merge("condition", fmt.Sprintf("filters[%d]", i), l.Filters[i].Validate(query.LinkCondition))
 	}
 
 	// Validate the format is valid
-	err := l.Format.Validate()
-	if err != nil {
-		return err
-	}
+	errs.Merge("linkStyle", "format", l.Format.Validate())
 
-	return nil
+	return errs.ErrOrNil()
 }
 
+// Validate checks if the link style format is valid, returning the problems
+// with every field as common.ValidationErrors.
 func (l *LinkStyleFormat) Validate() error {
-
-	hasAttribute := false
+	var errs common.ValidationErrors
 
 	// Validate the stroke is valid
-	err := common.IsValidColor("stroke", l.Stroke)
-	if err != nil {
-		return err
-	} else if l.Stroke != "" {
-		hasAttribute = true
-	}
+	errs.Add("linkStyle", "stroke", common.IsValidColor("stroke", l.Stroke))
 
 	// Validate the stroke width is valid integer suffixed with 'px'
-	err = common.IsValidPixel("stroke-width", l.StrokeWidth)
-	if err != nil {
-		return err
-	} else if l.StrokeWidth != "" {
-		hasAttribute = true
-	}
+	errs.Add("linkStyle", "stroke-width", common.IsValidPixel("stroke-width", l.StrokeWidth))
 
 	// Ensure at least one attribute is set
-	if !hasAttribute {
-		return fmt.Errorf("at least one 'format' attribute must be set")
+	if l.Stroke == "" && l.StrokeWidth == "" {
+		errs.Add("linkStyle", "", fmt.Errorf("at least one 'format' attribute must be set"))
 	}
 
-	return nil
+	return errs.ErrOrNil()
 }
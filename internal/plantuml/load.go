@@ -0,0 +1,38 @@
+package plantuml
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseYAML parses the YAML content into a PlantUML
+func ParseYAML(content string) (*PlantUML, error) {
+	var config PlantUML
+	err := yaml.Unmarshal([]byte(content), &config)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling YAML: %v", err)
+	}
+
+	// Specify the default values if they were not provided
+
+	if config.Direction == "" {
+		config.Direction = "TB"
+	}
+
+	return &config, nil
+}
+
+// LoadPlantUML loads and parses a single YAML PlantUML setting file from the given path.
+func LoadPlantUML(filePath string) (*PlantUML, error) {
+
+	// Read the file contents to a string
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %v", err)
+	}
+
+	// Parse the YAML
+	return ParseYAML(string(data))
+}
@@ -0,0 +1,258 @@
+package plantuml
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/diagram"
+	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
+)
+
+const (
+	// defaultElement is the element kind for nodes without a kind for their type
+	defaultElement = "component"
+	// defaultContainer is the element kind for subgraph nodes without a kind for their type
+	defaultContainer = "package"
+)
+
+// invalidAliasRegex matches the characters not allowed in a PlantUML alias
+var invalidAliasRegex = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// PlantUML contains the settings for generating the diagram.
+type PlantUML struct {
+	// The direction of the diagram (TB, LR)
+	Direction string `yaml:"direction"`
+	// The attribute to use as the node label (if set)
+	NodeLabel string `yaml:"nodeLabel"`
+	// The query to identify nodes to treat as subgraphs (nested containers), if not set every node with children is a container
+	SubgraphNodes query.Nodes `yaml:"subgraphNodes,omitempty"`
+	// The PlantUML element kind to use for each node type
+	Elements map[string]string `yaml:"elements,omitempty"`
+	// The style to apply to nodes
+	NodeStyle []NodeStyle `yaml:"nodeStyles,omitempty"`
+	// The style to apply to links
+	LinkStyle []LinkStyle `yaml:"linkStyles,omitempty"`
+}
+
+type NodeStyle struct {
+	// The query to identify nodes to format with the style
+	Filters []query.Filter `yaml:"filters"`
+	// The style to apply to the nodes
+	Format NodeStyleFormat `yaml:"format"`
+}
+
+type NodeStyleFormat struct {
+	Background string `yaml:"background,omitempty"`
+	LineColor  string `yaml:"lineColor,omitempty"`
+	LineStyle  string `yaml:"lineStyle,omitempty"`
+	TextColor  string `yaml:"textColor,omitempty"`
+}
+
+type LinkStyle struct {
+	// The query to identify links to format with the style
+	Filters []query.Filter `yaml:"filters"`
+	// The style to apply to the links
+	Format LinkStyleFormat `yaml:"format"`
+}
+
+type LinkStyleFormat struct {
+	Color     string `yaml:"color,omitempty"`
+	LineStyle string `yaml:"lineStyle,omitempty"`
+}
+
+// GeneratePlantUML creates a PlantUML component diagram from the config and
+// PlantUML settings. Nodes are placed inside the container of their nearest
+// ancestor that is rendered as a container.
+func GeneratePlantUML(config *configuration.Config, setting *PlantUML) (string, error) {
+	var uml strings.Builder
+
+	// IDs are rewritten as PlantUML aliases and cannot collide with another ID.
+	if err := diagram.CheckIDs(config, plantumlID); err != nil {
+		return "", err
+	}
+
+	// Write the header.
+	uml.WriteString("@startuml\n")
+	if setting.Direction == "LR" {
+		uml.WriteString("left to right direction\n")
+	} else {
+		uml.WriteString("top to bottom direction\n")
+	}
+
+	// Build a lookup for nodes.
+	nodeLookup := make(map[string]configuration.Node)
+	for _, node := range config.Nodes {
+		nodeLookup[node.ID] = node
+	}
+
+	// Determine which nodes are containers, only nodes with children can be a container.
+	candidates := config.Nodes
+	if len(setting.SubgraphNodes.Filters) > 0 {
+		subgraphConfig, err := query.ExecuteQuery(&query.Query{Nodes: setting.SubgraphNodes}, config)
+		if err != nil {
+			return "", fmt.Errorf("error executing subgraph query: %v", err)
+		}
+		candidates = subgraphConfig.Nodes
+	}
+	hasChildren := diagram.ParentIDs(config)
+	containers := make(map[string]bool)
+	for _, node := range candidates {
+		containers[node.ID] = hasChildren[node.ID]
+	}
+
+	// Place the containers and nodes in their nearest container ancestor.
+	tree := diagram.NewTree(config, containers)
+
+	// Determine the style of each node, later styles take precedence.
+	nodeFormats := make(map[string]*NodeStyleFormat)
+	for _, node := range config.Nodes {
+		nodeFormats[node.ID] = &NodeStyleFormat{}
+		if node.External {
+			nodeFormats[node.ID].LineStyle = "dashed"
+		}
+	}
+	for _, style := range setting.NodeStyle {
+		nodes, err := query.ExecuteQuery(&query.Query{Nodes: query.Nodes{Filters: style.Filters}}, config)
+		if err != nil {
+			return "", fmt.Errorf("error executing node style query: %v", err)
+		}
+		for _, node := range nodes.Nodes {
+			nodeFormats[node.ID].merge(style.Format)
+		}
+	}
+
+	// Helper: the declaration of an element without its body.
+	declaration := func(nodeID string, fallbackKind string) string {
+		node := nodeLookup[nodeID]
+		kind, exists := setting.Elements[node.Type]
+		if !exists {
+			kind = fallbackKind
+		}
+		label := nodeID
+		if val := configuration.NodeAttributeLabel(node, setting.NodeLabel); val != "" {
+			label = val
+		}
+		return fmt.Sprintf("%s \"%s\" as %s%s", kind, diagram.ReplaceQuotes(label), plantumlID(nodeID), nodeFormats[nodeID].print())
+	}
+
+	uml.WriteString("\n")
+	uml.WriteString("' Nodes\n")
+
+	// Recursive helper to output a container.
+	var outputContainer func(c *diagram.Container, indent string)
+	outputContainer = func(c *diagram.Container, indent string) {
+		uml.WriteString(fmt.Sprintf("%s%s {\n", indent, declaration(c.ID, defaultContainer)))
+		for _, nodeID := range c.Nodes {
+			uml.WriteString(fmt.Sprintf("%s  %s\n", indent, declaration(nodeID, defaultElement)))
+		}
+		for _, sub := range c.Containers {
+			outputContainer(sub, indent+"  ")
+		}
+		uml.WriteString(fmt.Sprintf("%s}\n", indent))
+	}
+
+	for _, c := range tree.Containers {
+		outputContainer(c, "")
+	}
+	for _, nodeID := range tree.Nodes {
+		uml.WriteString(declaration(nodeID, defaultElement))
+		uml.WriteString("\n")
+	}
+
+	// Determine the style of each link, later styles take precedence.
+	linkFormats := make(map[string]*LinkStyleFormat)
+	for _, link := range config.Links {
+		linkFormats[link.ID] = &LinkStyleFormat{}
+	}
+	for _, style := range setting.LinkStyle {
+		links, err := query.ExecuteQuery(&query.Query{Links: query.Links{Filters: style.Filters}}, config)
+		if err != nil {
+			return "", fmt.Errorf("error executing link style query: %v", err)
+		}
+		for _, link := range links.Links {
+			linkFormats[link.ID].merge(style.Format)
+		}
+	}
+
+	// Output the links sorted by their source and target.
+	links := diagram.SortLinks(config.Links)
+	if len(links) > 0 {
+		uml.WriteString("\n")
+		uml.WriteString("' Links\n")
+	}
+	for _, link := range links {
+		uml.WriteString(fmt.Sprintf("%s %s %s : %s\n", plantumlID(link.Source), linkFormats[link.ID].arrow(), plantumlID(link.Target), diagram.ReplaceQuotes(link.Type)))
+	}
+
+	uml.WriteString("@enduml\n")
+
+	return uml.String(), nil
+}
+
+// plantumlID returns the node ID in a form PlantUML accepts as an alias, replacing
+// the '/' in namespaced IDs such as 'payments/api' with '__' and any other
+// character that is not allowed, such as the '-' in 'order-api', with '_'.
+func plantumlID(id string) string {
+	id = strings.ReplaceAll(id, configuration.NamespaceSeparator, "__")
+	return invalidAliasRegex.ReplaceAllString(id, "_")
+}
+
+// merge sets the attributes of the other format that are set.
+func (f *NodeStyleFormat) merge(other NodeStyleFormat) {
+	diagram.SetIfNotEmpty(&f.Background, other.Background)
+	diagram.SetIfNotEmpty(&f.LineColor, other.LineColor)
+	diagram.SetIfNotEmpty(&f.LineStyle, other.LineStyle)
+	diagram.SetIfNotEmpty(&f.TextColor, other.TextColor)
+}
+
+// print returns the inline style of an element such as ' #back:DDEEFF;line:336699;line.dashed'.
+func (f NodeStyleFormat) print() string {
+	props := []string{}
+
+	if f.Background != "" {
+		props = append(props, fmt.Sprintf("back:%s", strings.TrimPrefix(f.Background, "#")))
+	}
+
+	if f.LineColor != "" {
+		props = append(props, fmt.Sprintf("line:%s", strings.TrimPrefix(f.LineColor, "#")))
+	}
+
+	if f.LineStyle != "" {
+		props = append(props, fmt.Sprintf("line.%s", f.LineStyle))
+	}
+
+	if f.TextColor != "" {
+		props = append(props, fmt.Sprintf("text:%s", strings.TrimPrefix(f.TextColor, "#")))
+	}
+
+	if len(props) == 0 {
+		return ""
+	}
+	return " #" + strings.Join(props, ";")
+}
+
+// merge sets the attributes of the other format that are set.
+func (l *LinkStyleFormat) merge(other LinkStyleFormat) {
+	diagram.SetIfNotEmpty(&l.Color, other.Color)
+	diagram.SetIfNotEmpty(&l.LineStyle, other.LineStyle)
+}
+
+// arrow returns the arrow for the link including its style such as '-[#336699,dashed]->'.
+func (l LinkStyleFormat) arrow() string {
+	props := []string{}
+
+	if l.Color != "" {
+		props = append(props, l.Color)
+	}
+
+	if l.LineStyle != "" {
+		props = append(props, l.LineStyle)
+	}
+
+	if len(props) == 0 {
+		return "-->"
+	}
+	return fmt.Sprintf("-[%s]->", strings.Join(props, ","))
+}
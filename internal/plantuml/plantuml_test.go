@@ -0,0 +1,175 @@
+package plantuml
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+)
+
+func TestGeneratePlantUML(t *testing.T) {
+	err := filepath.Walk("../../tests", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			configPath := filepath.Join(path, "config.yaml")
+			plantumlConfigPath := filepath.Join(path, "plantuml.yaml")
+			plantumlPath := filepath.Join(path, "plantuml.puml")
+
+			if _, err := os.Stat(configPath); os.IsNotExist(err) {
+				return nil
+			}
+			if _, err := os.Stat(plantumlConfigPath); os.IsNotExist(err) {
+				return nil
+			}
+			if _, err := os.Stat(plantumlPath); os.IsNotExist(err) {
+				return nil
+			}
+
+			relDir, err := filepath.Rel("../../tests", path)
+			if err != nil {
+				return err
+			}
+
+			sanitizedRelDir := strings.ReplaceAll(relDir, string(filepath.Separator), "#")
+
+			t.Run(sanitizedRelDir, func(t *testing.T) {
+				config, err := configuration.LoadConfig(configPath)
+				if err != nil {
+					t.Fatalf("Failed to load config: %v", err)
+				}
+
+				plantumlConfig, err := LoadPlantUML(plantumlConfigPath)
+				if err != nil {
+					t.Fatalf("Failed to load plantuml config: %v", err)
+				}
+
+				err = plantumlConfig.Validate()
+				if err != nil {
+					t.Fatalf("PlantUML config validation failed: %v", err)
+				}
+
+				expectedBytes, err := os.ReadFile(plantumlPath)
+				if err != nil {
+					t.Fatalf("Failed to read PlantUML file: %v", err)
+				}
+				expectedOutput := string(expectedBytes)
+
+				output, err := GeneratePlantUML(config, plantumlConfig)
+				if err != nil {
+					t.Fatalf("GeneratePlantUML returned error: %v", err)
+				}
+				if output != expectedOutput {
+					t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
+				}
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Error walking through example folder: %v", err)
+	}
+}
+
+func TestGeneratePlantUMLElements(t *testing.T) {
+	config, err := configuration.ParseYAML(`
+nodes:
+  - id: platform
+    type: System
+  - id: orders_db
+    type: Database
+    parent: platform
+  - id: api
+    type: Service
+    parent: platform
+links:
+  - source: api
+    target: orders_db
+    type: SQL
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	setting, err := ParseYAML(`
+subgraphNodes:
+  filters:
+    - condition:
+        field: type
+        operator: equals
+        value: System
+elements:
+  System: cloud
+  Database: database
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse settings: %v", err)
+	}
+
+	output, err := GeneratePlantUML(config, setting)
+	if err != nil {
+		t.Fatalf("GeneratePlantUML returned error: %v", err)
+	}
+
+	// Node types without an element kind fall back to a component, and containers to a package
+	expected := []string{
+		"cloud \"platform\" as platform {",
+		"  component \"api\" as api",
+		"  database \"orders_db\" as orders_db",
+		"api --> orders_db : SQL",
+	}
+	for _, line := range expected {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, output)
+		}
+	}
+}
+
+func TestGeneratePlantUMLAliases(t *testing.T) {
+	config, err := configuration.ParseYAML(`
+nodes:
+  - id: order-api
+    type: Service
+  - id: payments/api
+    type: Service
+links:
+  - source: order-api
+    target: payments/api
+    type: HTTP
+`)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	setting, err := ParseYAML(`direction: TB`)
+	if err != nil {
+		t.Fatalf("Failed to parse settings: %v", err)
+	}
+
+	output, err := GeneratePlantUML(config, setting)
+	if err != nil {
+		t.Fatalf("GeneratePlantUML returned error: %v", err)
+	}
+
+	expected := []string{
+		"component \"order-api\" as order_api",
+		"component \"payments/api\" as payments__api",
+		"order_api --> payments__api : HTTP",
+	}
+	for _, line := range expected {
+		if !strings.Contains(output, line) {
+			t.Errorf("Expected output to contain %q, got:\n%s", line, output)
+		}
+	}
+
+	// An ID that is already written in the rewritten form collides with it
+	config.Nodes = append(config.Nodes, configuration.Node{ID: "order_api", Type: "Service"})
+	_, err = GeneratePlantUML(config, setting)
+	expectedError := "node IDs 'order-api' and 'order_api' are both rendered as 'order_api'"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Expected error %q, got: %v", expectedError, err)
+	}
+}
@@ -0,0 +1,147 @@
+package plantuml
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/common"
+	query "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
+)
+
+// elementKinds are the PlantUML elements a node type can be rendered as, all of
+// them can also be used as a container for nested nodes
+var elementKinds = []string{
+	"artifact", "card", "cloud", "collections", "component", "database", "file", "folder",
+	"frame", "node", "package", "queue", "rectangle", "storage",
+}
+
+// nodeLineStyles are the PlantUML line styles that can be used in a node style
+var nodeLineStyles = []string{"dashed", "dotted", "bold"}
+
+// linkLineStyles are the PlantUML line styles that can be used in a link style
+var linkLineStyles = []string{"dashed", "dotted", "bold", "hidden"}
+
+// Validate checks if the PlantUML settings are valid, returning all of the
+// problems found as common.ValidationErrors.
+func (p *PlantUML) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the direction is valid, PlantUML only supports two directions
+	switch p.Direction {
+	case "TB":
+	case "LR":
+	default:
+		errs.Add("setting", "direction", fmt.Errorf("invalid direction: %s", p.Direction))
+	}
+
+	// Validate the node label is valid
+	if p.NodeLabel != "" {
+		// Perform same validation as attribute values
+		errs.Add("setting", "nodeLabel", common.IsValidValue(p.NodeLabel, "nodeLabel"))
+	}
+
+	// Validate the subgraph nodes are valid
+	errs.Merge("condition", "subgraphNodes", p.SubgraphNodes.Validate())
+
+	// Validate the element kinds for the node types
+	for _, nodeType := range sortedKeys(p.Elements) {
+		path := fmt.Sprintf("elements.%s", nodeType)
+		if !slices.Contains(elementKinds, p.Elements[nodeType]) {
+			errs.Add("setting", path, fmt.Errorf("invalid element kind '%s' for node type '%s'", p.Elements[nodeType], nodeType))
+		}
+	}
+
+	// Validate all of the node styles
+	for i := range p.NodeStyle {
+		errs.Merge("nodeStyle", fmt.Sprintf("nodeStyles[%d]", i), p.NodeStyle[i].Validate())
+	}
+
+	// Validate all of the link styles
+	for i := range p.LinkStyle {
+		errs.Merge("linkStyle", fmt.Sprintf("linkStyles[%d]", i), p.LinkStyle[i].Validate())
+	}
+
+	return errs.ErrOrNil()
+}
+
+func (n *NodeStyle) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the filters are valid
+	for i := range n.Filters {
+		errs.Merge("condition", fmt.Sprintf("filters[%d]", i), n.Filters[i].Validate(query.NodeCondition))
+	}
+
+	// Validate the format is valid
+	errs.Merge("nodeStyle", "format", n.Format.Validate())
+
+	return errs.ErrOrNil()
+}
+
+// Validate checks if the node style format is valid, returning the problems
+// with every field as common.ValidationErrors.
+func (n *NodeStyleFormat) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the colors are valid
+	errs.Add("nodeStyle", "background", common.IsValidColor("background", n.Background))
+	errs.Add("nodeStyle", "lineColor", common.IsValidColor("lineColor", n.LineColor))
+	errs.Add("nodeStyle", "textColor", common.IsValidColor("textColor", n.TextColor))
+
+	// Validate the line style is valid
+	if n.LineStyle != "" && !slices.Contains(nodeLineStyles, n.LineStyle) {
+		errs.Add("nodeStyle", "lineStyle", fmt.Errorf("invalid lineStyle: '%s'", n.LineStyle))
+	}
+
+	// Ensure at least one attribute is set
+	if *n == (NodeStyleFormat{}) {
+		errs.Add("nodeStyle", "", fmt.Errorf("at least one 'format' attribute must be set"))
+	}
+
+	return errs.ErrOrNil()
+}
+
+func (l *LinkStyle) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the filters are valid
+	for i := range l.Filters {
+		errs.Merge("condition", fmt.Sprintf("filters[%d]", i), l.Filters[i].Validate(query.LinkCondition))
+	}
+
+	// Validate the format is valid
+	errs.Merge("linkStyle", "format", l.Format.Validate())
+
+	return errs.ErrOrNil()
+}
+
+// Validate checks if the link style format is valid, returning the problems
+// with every field as common.ValidationErrors.
+func (l *LinkStyleFormat) Validate() error {
+	var errs common.ValidationErrors
+
+	// Validate the color is valid
+	errs.Add("linkStyle", "color", common.IsValidColor("color", l.Color))
+
+	// Validate the line style is valid
+	if l.LineStyle != "" && !slices.Contains(linkLineStyles, l.LineStyle) {
+		errs.Add("linkStyle", "lineStyle", fmt.Errorf("invalid lineStyle: '%s'", l.LineStyle))
+	}
+
+	// Ensure at least one attribute is set
+	if *l == (LinkStyleFormat{}) {
+		errs.Add("linkStyle", "", fmt.Errorf("at least one 'format' attribute must be set"))
+	}
+
+	return errs.ErrOrNil()
+}
+
+// sortedKeys returns the keys of the map in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
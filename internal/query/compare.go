@@ -0,0 +1,160 @@
+package query
+
+import (
+	"cmp"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+)
+
+// versionRe matches version-like strings such as '2', '1.10.3', 'v2.0.0-rc.1' or '1.0.0+build'
+var versionRe = regexp.MustCompile(`^v?(\d+(?:\.\d+)*)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// isComparableValue checks if a value can be used with the ordering operators.
+func isComparableValue(value string) bool {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return true
+	}
+	return versionRe.MatchString(value)
+}
+
+// compareValues compares a field value against a query value. Values are
+// compared numerically if both are numbers, including quoted decimals such as
+// '0.75', otherwise as versions if both are version-like. A version is only
+// recognized when it cannot be read as a number, such as '1.10.3', 'v1.10' or
+// '2.0-rc.1'. The boolean result is false if the values are not comparable.
+func compareValues(fieldValue any, value string) (int, bool) {
+	var fieldStr string
+	switch v := fieldValue.(type) {
+	case nil, bool, []any, map[string]any:
+		return 0, false
+	case string:
+		fieldStr = v
+	default:
+		fieldStr = configuration.FormatAttributeValue(v)
+	}
+
+	// Compare as numbers when possible
+	a, errA := strconv.ParseFloat(fieldStr, 64)
+	b, errB := strconv.ParseFloat(value, 64)
+	if errA == nil && errB == nil {
+		return cmp.Compare(a, b), true
+	}
+
+	// Fall back to comparing as versions
+	return compareVersions(fieldStr, value)
+}
+
+// compareVersions compares two version-like strings following semantic
+// versioning precedence. Missing numeric components are treated as zero.
+func compareVersions(a string, b string) (int, bool) {
+	matchA := versionRe.FindStringSubmatch(a)
+	matchB := versionRe.FindStringSubmatch(b)
+	if matchA == nil || matchB == nil {
+		return 0, false
+	}
+
+	// Compare the numeric components
+	partsA := strings.Split(matchA[1], ".")
+	partsB := strings.Split(matchB[1], ".")
+	for i := 0; i < max(len(partsA), len(partsB)); i++ {
+		numA, numB := 0, 0
+		if i < len(partsA) {
+			numA, _ = strconv.Atoi(partsA[i])
+		}
+		if i < len(partsB) {
+			numB, _ = strconv.Atoi(partsB[i])
+		}
+		if numA != numB {
+			return cmp.Compare(numA, numB), true
+		}
+	}
+
+	// A version without a pre-release has higher precedence than one with
+	preA, preB := matchA[2], matchB[2]
+	switch {
+	case preA == preB:
+		return 0, true
+	case preA == "":
+		return 1, true
+	case preB == "":
+		return -1, true
+	}
+
+	// Compare the pre-release identifiers one at a time
+	idsA := strings.Split(preA, ".")
+	idsB := strings.Split(preB, ".")
+	for i := 0; i < min(len(idsA), len(idsB)); i++ {
+		if result := comparePrereleaseIdentifier(idsA[i], idsB[i]); result != 0 {
+			return result, true
+		}
+	}
+
+	return cmp.Compare(len(idsA), len(idsB)), true
+}
+
+// comparePrereleaseIdentifier compares a single pre-release identifier where
+// numeric identifiers have lower precedence than alphanumeric ones.
+func comparePrereleaseIdentifier(a string, b string) int {
+	numA, errA := strconv.Atoi(a)
+	numB, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(numA, numB)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// valueSatisfiesOrdering checks if a field value satisfies an ordering operator
+// against the query value. Lists match if any item satisfies the operator.
+func valueSatisfiesOrdering(fieldValue any, operator string, value string) bool {
+	if list, ok := fieldValue.([]any); ok {
+		for _, item := range list {
+			if valueSatisfiesOrdering(item, operator, value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	result, ok := compareValues(fieldValue, value)
+	if !ok {
+		return false
+	}
+
+	switch operator {
+	case "greaterThan":
+		return result > 0
+	case "lessThan":
+		return result < 0
+	case "greaterOrEqual":
+		return result >= 0
+	case "lessOrEqual":
+		return result <= 0
+	default:
+		return false
+	}
+}
+
+// valueBetween checks if a field value is within the inclusive range of the
+// lower and upper values. Lists match if any item is within the range.
+func valueBetween(fieldValue any, lower string, upper string) bool {
+	if list, ok := fieldValue.([]any); ok {
+		for _, item := range list {
+			if valueBetween(item, lower, upper) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return valueSatisfiesOrdering(fieldValue, "greaterOrEqual", lower) &&
+		valueSatisfiesOrdering(fieldValue, "lessOrEqual", upper)
+}
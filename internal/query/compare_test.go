@@ -0,0 +1,46 @@
+package query
+
+import (
+	"testing"
+)
+
+func TestCompareValues(t *testing.T) {
+	tests := []struct {
+		name       string
+		fieldValue any
+		value      string
+		expected   int
+		comparable bool
+	}{
+		{"int_less", 3, "5", -1, true},
+		{"int_equal", 3, "3", 0, true},
+		{"float_greater", 1200.5, "1000", 1, true},
+		{"numeric_string", "10", "9", 1, true},
+		{"float_decimal", 1.5, "1.25", 1, true},
+		{"quoted_decimal", "0.75", "0.8", -1, true},
+		{"quoted_decimal_greater", "1.10", "1.9", -1, true},
+		{"version_string", "1.10.0", "1.9", 1, true},
+		{"version_string_equal", "1.10", "1.10.0", 0, true},
+		{"version_prefix_two_parts", "v1.10", "v1.9", 1, true},
+		{"version_less", "1.10.3", "2.0.0", -1, true},
+		{"version_numeric_components", "1.10.0", "1.9.0", 1, true},
+		{"version_missing_components", "2", "2.0.0", 0, true},
+		{"version_prefix", "v2.1.0", "2.0.0", 1, true},
+		{"prerelease_lower", "2.0.0-rc.1", "2.0.0", -1, true},
+		{"prerelease_numeric", "2.0.0-rc.2", "2.0.0-rc.10", -1, true},
+		{"prerelease_alpha", "2.0.0-alpha", "2.0.0-beta", -1, true},
+		{"build_metadata_ignored", "2.0.0+build.5", "2.0.0", 0, true},
+		{"not_comparable_string", "Java", "5", 0, false},
+		{"not_comparable_bool", true, "1", 0, false},
+		{"not_comparable_nil", nil, "1", 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, comparable := compareValues(test.fieldValue, test.value)
+			if actual != test.expected || comparable != test.comparable {
+				t.Errorf("compareValues(%v, %q) = %d, %v; want %d, %v", test.fieldValue, test.value, actual, comparable, test.expected, test.comparable)
+			}
+		})
+	}
+}
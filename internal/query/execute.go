@@ -3,15 +3,28 @@ package query
 import (
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
 )
 
+const (
+	// BoundaryStub keeps links leaving the result by adding the outside node as an external stub
+	BoundaryStub = "stub"
+	// BoundaryAncestor keeps links leaving the result by collapsing the outside node to its top-level ancestor
+	BoundaryAncestor = "ancestor"
+)
+
 // ConfigContext holds the configuration with pre-calculated maps for efficient querying
 type ConfigContext struct {
-	Config      *configuration.Config
-	NodesById   map[string]*configuration.Node
-	ChildrenMap map[string][]string // Maps parent node ID to list of child node IDs
+	Config        *configuration.Config
+	NodesById     map[string]*configuration.Node
+	ChildrenMap   map[string][]string              // Maps parent node ID to list of child node IDs
+	OutboundLinks map[string][]*configuration.Link // Maps node ID to the links where it is the source
+	InboundLinks  map[string][]*configuration.Link // Maps node ID to the links where it is the target
+
+	// reachableCache stores the results of link traversals keyed by their parameters
+	reachableCache map[string]map[string]int
 }
 
 // NewConfigContext creates a new ConfigContext with pre-calculated maps for efficient querying
@@ -30,10 +43,22 @@ func NewConfigContext(config *configuration.Config) *ConfigContext {
 		}
 	}
 
+	// Create the adjacency maps of links in both directions
+	outboundLinks := make(map[string][]*configuration.Link)
+	inboundLinks := make(map[string][]*configuration.Link)
+	for i := range config.Links {
+		link := &config.Links[i]
+		outboundLinks[link.Source] = append(outboundLinks[link.Source], link)
+		inboundLinks[link.Target] = append(inboundLinks[link.Target], link)
+	}
+
 	return &ConfigContext{
-		Config:      config,
-		NodesById:   nodesById,
-		ChildrenMap: childrenMap,
+		Config:         config,
+		NodesById:      nodesById,
+		ChildrenMap:    childrenMap,
+		OutboundLinks:  outboundLinks,
+		InboundLinks:   inboundLinks,
+		reachableCache: make(map[string]map[string]int),
 	}
 }
 
@@ -41,6 +66,14 @@ func NewConfigContext(config *configuration.Config) *ConfigContext {
 // It returns a new configuration containing only the nodes and links
 // that match the query conditions.
 func ExecuteQuery(query *Query, config *configuration.Config) (configuration.Config, error) {
+	// Compile the regular expressions once instead of for every node and link
+	if err := compileRegexes(query.Nodes.Filters); err != nil {
+		return configuration.Config{}, err
+	}
+	if err := compileRegexes(query.Links.Filters); err != nil {
+		return configuration.Config{}, err
+	}
+
 	// Create context with pre-calculated maps for efficient querying
 	ctx := NewConfigContext(config)
 
@@ -51,25 +84,63 @@ func ExecuteQuery(query *Query, config *configuration.Config) (configuration.Con
 	}
 
 	// Iterate over all nodes and apply filters
+	nodeIDs := make(map[string]bool)
 	for _, node := range config.Nodes {
 		matchesAllFilters, err := nodeMatchesAllFilters(node, query.Nodes.Filters, ctx)
 		if err != nil {
 			return configuration.Config{}, fmt.Errorf("error applying filters to node '%s': %w", node.ID, err)
 		}
 		if matchesAllFilters {
-			filteredConfig.Nodes = append(filteredConfig.Nodes, node)
+			nodeIDs[node.ID] = true
 		}
 	}
 
-	// Create a map lf all of the node IDs so we can easily check if a node exists
-	nodeIDs := make(map[string]bool)
-	for _, node := range filteredConfig.Nodes {
-		nodeIDs[node.ID] = true
+	// Pull in the neighbors of the matched nodes
+	if query.Expand.Depth > 0 {
+		direction := query.Expand.Direction
+		if direction == "" {
+			direction = DirectionBoth
+		}
+
+		matched := make([]string, 0, len(nodeIDs))
+		for id := range nodeIDs {
+			matched = append(matched, id)
+		}
+		for _, id := range matched {
+			for neighbor := range ctx.Reachable(id, direction, query.Expand.LinkTypes, query.Expand.Depth) {
+				nodeIDs[neighbor] = true
+			}
+		}
 	}
 
+	// Pull in the ancestors of the selected nodes to keep their grouping
+	if query.Hierarchy.IncludeAncestors {
+		selected := make([]string, 0, len(nodeIDs))
+		for id := range nodeIDs {
+			selected = append(selected, id)
+		}
+		for _, id := range selected {
+			for _, ancestor := range ancestorIDs(id, ctx) {
+				nodeIDs[ancestor] = true
+			}
+		}
+	}
+
+	// Keep the selected nodes in their original order
+	for _, node := range config.Nodes {
+		if nodeIDs[node.ID] {
+			filteredConfig.Nodes = append(filteredConfig.Nodes, node)
+		}
+	}
+
+	// Track the nodes outside of the result that are referenced by boundary links
+	// and the links already in the result so collapsed links are not duplicated
+	externalIDs := make(map[string]bool)
+	resultLinks := make(map[string]bool)
+
 	// Iterate over all links and include only those where both source and target are in filtered nodes
 	for _, link := range config.Links {
-		matchesAllFilters, err := linkMatchesAllFilters(link, query.Links.Filters)
+		matchesAllFilters, err := linkMatchesAllFilters(link, query.Links.Filters, ctx)
 		if err != nil {
 			return configuration.Config{}, fmt.Errorf("error applying filters to link '%s' -> '%s': %w", link.Source, link.Target, err)
 		}
@@ -78,24 +149,77 @@ func ExecuteQuery(query *Query, config *configuration.Config) (configuration.Con
 		}
 
 		if nodeIDs[link.Source] && nodeIDs[link.Target] {
+			resultLinks[link.Source+"|"+link.Target+"|"+link.Type] = true
+			filteredConfig.Links = append(filteredConfig.Links, link)
+		} else if query.Boundary.Mode != "" && (nodeIDs[link.Source] || nodeIDs[link.Target]) {
+			// Keep the link by replacing the endpoint outside of the result
+			if !nodeIDs[link.Source] {
+				link.Source = boundaryEndpoint(link.Source, query.Boundary.Mode, nodeIDs, ctx)
+			} else {
+				link.Target = boundaryEndpoint(link.Target, query.Boundary.Mode, nodeIDs, ctx)
+			}
+
+			// Collapsing to an ancestor can link a node to itself or produce the
+			// same link more than once
+			key := link.Source + "|" + link.Target + "|" + link.Type
+			if link.Source == link.Target || resultLinks[key] {
+				continue
+			}
+			resultLinks[key] = true
+
+			for _, endpoint := range []string{link.Source, link.Target} {
+				if !nodeIDs[endpoint] {
+					externalIDs[endpoint] = true
+				}
+			}
 			filteredConfig.Links = append(filteredConfig.Links, link)
 		}
 	}
 
 	// Loop through all of the nodes, if the parent is not in the nodeIds map, remove the reference to the parent
+	// or re-parent the node to the nearest ancestor that is in the result
 	for i, node := range filteredConfig.Nodes {
 		if _, exists := nodeIDs[node.Parent]; !exists {
 			filteredConfig.Nodes[i].Parent = ""
+			if query.Hierarchy.Reparent {
+				for _, ancestor := range ancestorIDs(node.ID, ctx) {
+					if nodeIDs[ancestor] {
+						filteredConfig.Nodes[i].Parent = ancestor
+						break
+					}
+				}
+			}
+		}
+	}
+
+	// Add the stubs for the nodes outside of the result referenced by boundary links
+	for _, node := range config.Nodes {
+		if externalIDs[node.ID] {
+			node.Parent = ""
+			node.External = true
+			filteredConfig.Nodes = append(filteredConfig.Nodes, node)
 		}
 	}
 
 	return filteredConfig, nil
 }
 
+// boundaryEndpoint returns the node ID to use in place of a link endpoint that
+// is outside of the query result.
+func boundaryEndpoint(nodeID string, mode string, nodeIDs map[string]bool, ctx *ConfigContext) string {
+	if mode == BoundaryAncestor {
+		// Collapse the node to its top-level ancestor
+		if ancestors := ancestorIDs(nodeID, ctx); len(ancestors) > 0 {
+			nodeID = ancestors[len(ancestors)-1]
+		}
+	}
+	return nodeID
+}
+
 // linkMatchesAllFilters checks if a link satisfies all the provided filters.
-func linkMatchesAllFilters(link configuration.Link, filters []Filter) (bool, error) {
+func linkMatchesAllFilters(link configuration.Link, filters []Filter, ctx *ConfigContext) (bool, error) {
 	for _, filter := range filters {
-		matches, err := linkMatchesFilter(link, filter)
+		matches, err := linkMatchesFilter(link, filter, ctx)
 		if err != nil {
 			return false, err
 		}
@@ -107,9 +231,9 @@ func linkMatchesAllFilters(link configuration.Link, filters []Filter) (bool, err
 }
 
 // linkMatchesFilter checks if a link satisfies a single filter condition.
-func linkMatchesFilter(link configuration.Link, filter Filter) (bool, error) {
+func linkMatchesFilter(link configuration.Link, filter Filter, ctx *ConfigContext) (bool, error) {
 	// Extract the value of the specified field from the link
-	fieldValue, err := getLinkFieldValue(link, filter.Condition.Field)
+	fieldValue, err := getLinkFieldValue(link, filter.Condition.Field, ctx)
 	if err != nil {
 		return false, err
 	}
@@ -117,11 +241,31 @@ func linkMatchesFilter(link configuration.Link, filter Filter) (bool, error) {
 	// Perform the comparison based on the operator
 	switch filter.Condition.Operator {
 	case "equals":
-		return fieldValue == filter.Condition.Value, nil
+		return valueEquals(fieldValue, filter.Condition.Value), nil
 	case "notEquals":
-		return fieldValue != filter.Condition.Value, nil
+		return !valueEquals(fieldValue, filter.Condition.Value), nil
+	case "greaterThan", "lessThan", "greaterOrEqual", "lessOrEqual":
+		return valueSatisfiesOrdering(fieldValue, filter.Condition.Operator, filter.Condition.Value), nil
+	case "between":
+		if len(filter.Condition.Values) != 2 {
+			return false, fmt.Errorf("operator 'between' requires exactly 2 values")
+		}
+		return valueBetween(fieldValue, filter.Condition.Values[0], filter.Condition.Values[1]), nil
+	case "contains", "startsWith", "endsWith":
+		return valueMatchesString(fieldValue, filter.Condition.Operator, filter.Condition.Value), nil
+	case "matches":
+		re, err := filter.Condition.compiledRegex()
+		if err != nil {
+			return false, err
+		}
+		return valueMatchesRegex(fieldValue, re), nil
+	case "in":
+		return valueIn(fieldValue, filter.Condition.Values), nil
+	case "notIn":
+		return !valueIn(fieldValue, filter.Condition.Values), nil
 	case "exists":
-		switch filter.Condition.Field {
+		field := filter.Condition.Field
+		switch field {
 		case "source":
 			return link.Source != "", nil
 		case "target":
@@ -129,7 +273,14 @@ func linkMatchesFilter(link configuration.Link, filter Filter) (bool, error) {
 		case "type":
 			return link.Type != "", nil
 		default:
-			field := filter.Condition.Field
+			// Check the fields of the source or target node
+			if endpoint, nodeField, ok := splitEndpointField(field); ok {
+				node, exists := ctx.NodesById[linkEndpointID(link, endpoint)]
+				if !exists {
+					return false, nil
+				}
+				return nodeFieldExists(*node, nodeField), nil
+			}
 
 			// Check in Attributes, if 'attribute.' prefix remove it and look up attribute
 			if len(field) > 10 && field[:10] == "attribute." {
@@ -138,13 +289,13 @@ func linkMatchesFilter(link configuration.Link, filter Filter) (bool, error) {
 				return false, nil
 			}
 
-			_, exists := link.Attributes[field]
+			_, exists := configuration.LookupAttribute(link.Attributes, field)
 			return exists, nil
 		}
 	case "and":
 		// Check if all conditions are met
 		for _, condition := range filter.Condition.Conditions {
-			matches, err := linkMatchesFilter(link, Filter{Condition: condition})
+			matches, err := linkMatchesFilter(link, Filter{Condition: condition}, ctx)
 			if err != nil {
 				return false, err
 			}
@@ -156,7 +307,7 @@ func linkMatchesFilter(link configuration.Link, filter Filter) (bool, error) {
 	case "or":
 		// Check if any condition is met
 		for _, condition := range filter.Condition.Conditions {
-			matches, err := linkMatchesFilter(link, Filter{Condition: condition})
+			matches, err := linkMatchesFilter(link, Filter{Condition: condition}, ctx)
 			if err != nil {
 				return false, err
 			}
@@ -165,14 +316,61 @@ func linkMatchesFilter(link configuration.Link, filter Filter) (bool, error) {
 			}
 		}
 		return false, nil
+	case "not":
+		// Check that the single nested condition is not met
+		if len(filter.Condition.Conditions) != 1 {
+			return false, fmt.Errorf("operator 'not' requires exactly 1 condition")
+		}
+		matches, err := linkMatchesFilter(link, Filter{Condition: filter.Condition.Conditions[0]}, ctx)
+		if err != nil {
+			return false, err
+		}
+		return !matches, nil
+	case "ancestorOf":
+		return isAncestorOf(linkEndpointID(link, filter.Condition.Field), filter.Condition.Value, ctx)
+	case "descendantOf":
+		return isDescendantOf(linkEndpointID(link, filter.Condition.Field), filter.Condition.Value, ctx)
+	case "parentOf":
+		return isParentOf(linkEndpointID(link, filter.Condition.Field), filter.Condition.Value, ctx)
+	case "childOf":
+		return isChildOf(linkEndpointID(link, filter.Condition.Field), filter.Condition.Value, ctx)
+	case "downstreamOf":
+		return isReachable(linkEndpointID(link, filter.Condition.Field), filter.Condition.Value, DirectionOut, filter.Condition.LinkTypes, filter.Condition.Depth, ctx)
+	case "upstreamOf":
+		return isReachable(linkEndpointID(link, filter.Condition.Field), filter.Condition.Value, DirectionIn, filter.Condition.LinkTypes, filter.Condition.Depth, ctx)
+	case "connectedTo":
+		return isReachable(linkEndpointID(link, filter.Condition.Field), filter.Condition.Value, DirectionBoth, filter.Condition.LinkTypes, filter.Condition.Depth, ctx)
+	case "directlyCalls":
+		return isReachable(linkEndpointID(link, filter.Condition.Field), filter.Condition.Value, DirectionIn, filter.Condition.LinkTypes, 1, ctx)
+	case "calledBy":
+		return isReachable(linkEndpointID(link, filter.Condition.Field), filter.Condition.Value, DirectionOut, filter.Condition.LinkTypes, 1, ctx)
 	default:
 		return false, fmt.Errorf("unsupported operator '%s'", filter.Condition.Operator)
 	}
 }
 
+// linkEndpointID returns the ID of the node at the 'source' or 'target' end of a link.
+func linkEndpointID(link configuration.Link, endpoint string) string {
+	if endpoint == "source" {
+		return link.Source
+	}
+	return link.Target
+}
+
+// splitEndpointField splits a link field such as 'source.type' or
+// 'target.attribute.team' into the endpoint and the node field.
+func splitEndpointField(field string) (string, string, bool) {
+	endpoint, nodeField, found := strings.Cut(field, ".")
+	if !found || (endpoint != "source" && endpoint != "target") {
+		return "", "", false
+	}
+	return endpoint, nodeField, true
+}
+
 // getLinkFieldValue retrieves the value of a specified field from a link.
-// It first checks the top-level fields, then the Attributes map.
-func getLinkFieldValue(link configuration.Link, field string) (string, error) {
+// It first checks the top-level fields, then the Attributes map, returning
+// attribute values with their original YAML type.
+func getLinkFieldValue(link configuration.Link, field string, ctx *ConfigContext) (any, error) {
 	switch field {
 	case "source":
 		return link.Source, nil
@@ -181,23 +379,43 @@ func getLinkFieldValue(link configuration.Link, field string) (string, error) {
 	case "type":
 		return link.Type, nil
 	default:
+		// Resolve fields of the source or target node
+		if endpoint, nodeField, ok := splitEndpointField(field); ok {
+			node, exists := ctx.NodesById[linkEndpointID(link, endpoint)]
+			if !exists {
+				return nil, nil
+			}
+			return getNodeFieldValue(*node, nodeField)
+		}
+
 		// Check in Attributes, if 'attribute.' prefix remove it and look up attribute
 		if len(field) > 10 && field[:10] == "attribute." {
 			field = field[10:]
 		} else {
-			return "", nil
+			return nil, nil
 		}
 
-		if val, exists := link.Attributes[field]; exists {
-			// Assuming all attribute values are strings for simplicity
-			if strVal, ok := val.(string); ok {
-				return strVal, nil
-			}
-			return "", fmt.Errorf("attribute '%s' is not a string", field)
+		if val, exists := configuration.LookupAttribute(link.Attributes, field); exists {
+			return val, nil
 		}
 
-		return "", nil
+		return nil, nil
+	}
+}
+
+// valueEquals checks if a field value matches the expected string. Scalars are
+// compared by their string representation and lists match if any item matches.
+func valueEquals(fieldValue any, expected string) bool {
+	if list, ok := fieldValue.([]any); ok {
+		for _, item := range list {
+			if valueEquals(item, expected) {
+				return true
+			}
+		}
+		return false
 	}
+
+	return configuration.FormatAttributeValue(fieldValue) == expected
 }
 
 // nodeMatchesAllFilters checks if a node satisfies all the provided filters.
@@ -225,30 +443,30 @@ func nodeMatchesFilter(node configuration.Node, filter Filter, ctx *ConfigContex
 	// Perform the comparison based on the operator
 	switch filter.Condition.Operator {
 	case "equals":
-		return fieldValue == filter.Condition.Value, nil
+		return valueEquals(fieldValue, filter.Condition.Value), nil
 	case "notEquals":
-		return fieldValue != filter.Condition.Value, nil
-	case "exists":
-		switch filter.Condition.Field {
-		case "id":
-			return node.ID != "", nil
-		case "type":
-			return node.Type != "", nil
-		case "parent":
-			return node.Parent != "", nil
-		default:
-			field := filter.Condition.Field
-
-			// Check in Attributes, if 'attribute.' prefix remove it and look up attribute
-			if len(field) > 10 && field[:10] == "attribute." {
-				field = field[10:]
-			} else {
-				return false, nil
-			}
-
-			_, exists := node.Attributes[field]
-			return exists, nil
+		return !valueEquals(fieldValue, filter.Condition.Value), nil
+	case "greaterThan", "lessThan", "greaterOrEqual", "lessOrEqual":
+		return valueSatisfiesOrdering(fieldValue, filter.Condition.Operator, filter.Condition.Value), nil
+	case "between":
+		if len(filter.Condition.Values) != 2 {
+			return false, fmt.Errorf("operator 'between' requires exactly 2 values")
 		}
+		return valueBetween(fieldValue, filter.Condition.Values[0], filter.Condition.Values[1]), nil
+	case "contains", "startsWith", "endsWith":
+		return valueMatchesString(fieldValue, filter.Condition.Operator, filter.Condition.Value), nil
+	case "matches":
+		re, err := filter.Condition.compiledRegex()
+		if err != nil {
+			return false, err
+		}
+		return valueMatchesRegex(fieldValue, re), nil
+	case "in":
+		return valueIn(fieldValue, filter.Condition.Values), nil
+	case "notIn":
+		return !valueIn(fieldValue, filter.Condition.Values), nil
+	case "exists":
+		return nodeFieldExists(node, filter.Condition.Field), nil
 	case "and":
 		// Check if all conditions are met
 		for _, condition := range filter.Condition.Conditions {
@@ -273,6 +491,26 @@ func nodeMatchesFilter(node configuration.Node, filter Filter, ctx *ConfigContex
 			}
 		}
 		return false, nil
+	case "not":
+		// Check that the single nested condition is not met
+		if len(filter.Condition.Conditions) != 1 {
+			return false, fmt.Errorf("operator 'not' requires exactly 1 condition")
+		}
+		matches, err := nodeMatchesFilter(node, Filter{Condition: filter.Condition.Conditions[0]}, ctx)
+		if err != nil {
+			return false, err
+		}
+		return !matches, nil
+	case "downstreamOf":
+		return isReachable(node.ID, filter.Condition.Value, DirectionOut, filter.Condition.LinkTypes, filter.Condition.Depth, ctx)
+	case "upstreamOf":
+		return isReachable(node.ID, filter.Condition.Value, DirectionIn, filter.Condition.LinkTypes, filter.Condition.Depth, ctx)
+	case "connectedTo":
+		return isReachable(node.ID, filter.Condition.Value, DirectionBoth, filter.Condition.LinkTypes, filter.Condition.Depth, ctx)
+	case "directlyCalls":
+		return isReachable(node.ID, filter.Condition.Value, DirectionIn, filter.Condition.LinkTypes, 1, ctx)
+	case "calledBy":
+		return isReachable(node.ID, filter.Condition.Value, DirectionOut, filter.Condition.LinkTypes, 1, ctx)
 	case "ancestorOf":
 		return isAncestorOf(node.ID, filter.Condition.Value, ctx)
 	case "descendantOf":
@@ -286,9 +524,32 @@ func nodeMatchesFilter(node configuration.Node, filter Filter, ctx *ConfigContex
 	}
 }
 
+// nodeFieldExists checks if the specified field is set on a node.
+func nodeFieldExists(node configuration.Node, field string) bool {
+	switch field {
+	case "id":
+		return node.ID != ""
+	case "type":
+		return node.Type != ""
+	case "parent":
+		return node.Parent != ""
+	default:
+		// Check in Attributes, if 'attribute.' prefix remove it and look up attribute
+		if len(field) > 10 && field[:10] == "attribute." {
+			field = field[10:]
+		} else {
+			return false
+		}
+
+		_, exists := configuration.LookupAttribute(node.Attributes, field)
+		return exists
+	}
+}
+
 // getNodeFieldValue retrieves the value of a specified field from a node.
-// It first checks the top-level fields, then the Attributes map.
-func getNodeFieldValue(node configuration.Node, field string) (string, error) {
+// It first checks the top-level fields, then the Attributes map, returning
+// attribute values with their original YAML type.
+func getNodeFieldValue(node configuration.Node, field string) (any, error) {
 	switch field {
 	case "id":
 		return node.ID, nil
@@ -301,18 +562,14 @@ func getNodeFieldValue(node configuration.Node, field string) (string, error) {
 		if len(field) > 10 && field[:10] == "attribute." {
 			field = field[10:]
 		} else {
-			return "", nil
+			return nil, nil
 		}
 
-		if val, exists := node.Attributes[field]; exists {
-			// Assuming all attribute values are strings for simplicity
-			if strVal, ok := val.(string); ok {
-				return strVal, nil
-			}
-			return "", fmt.Errorf("attribute '%s' is not a string", field)
+		if val, exists := configuration.LookupAttribute(node.Attributes, field); exists {
+			return val, nil
 		}
 
-		return "", nil
+		return nil, nil
 	}
 }
 
@@ -401,6 +658,38 @@ func isDescendantOf(nodeID string, targetNodeID string, ctx *ConfigContext) (boo
 	return false, nil
 }
 
+// ancestorIDs returns the IDs of the ancestors of a node ordered from the
+// direct parent up to the root of the hierarchy.
+func ancestorIDs(nodeID string, ctx *ConfigContext) []string {
+	ancestors := []string{}
+
+	node, exists := ctx.NodesById[nodeID]
+	if !exists {
+		return ancestors
+	}
+
+	// Traverse up the ancestor chain, guarding against cycles
+	visited := map[string]bool{nodeID: true}
+	for currentParentID := node.Parent; currentParentID != "" && !visited[currentParentID]; {
+		visited[currentParentID] = true
+		ancestors = append(ancestors, currentParentID)
+
+		parent, exists := ctx.NodesById[currentParentID]
+		if !exists {
+			break
+		}
+		currentParentID = parent.Parent
+	}
+
+	return ancestors
+}
+
+// Depth returns the depth of the node in the parent hierarchy, nodes without
+// a parent have a depth of 1.
+func (ctx *ConfigContext) Depth(nodeID string) int {
+	return len(ancestorIDs(nodeID, ctx)) + 1
+}
+
 // isAncestorOf checks if nodeID is an ancestor of targetNodeID in the given configuration context.
 // An ancestor is a node in the direct parent chain (parent, parent's parent, etc.)
 func isAncestorOf(nodeID string, targetNodeID string, ctx *ConfigContext) (bool, error) {
@@ -0,0 +1,51 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+)
+
+func TestExecuteQueryBoundaryAncestorLinks(t *testing.T) {
+	config := &configuration.Config{
+		Nodes: []configuration.Node{
+			{ID: "web", Type: "service"},
+			{ID: "platform", Type: "group"},
+			{ID: "api", Type: "service", Parent: "platform"},
+			{ID: "db", Type: "database", Parent: "platform"},
+		},
+		Links: []configuration.Link{
+			{Source: "web", Target: "platform", Type: "REST"},
+			{Source: "web", Target: "api", Type: "REST"},
+			{Source: "web", Target: "db", Type: "REST"},
+			{Source: "platform", Target: "api", Type: "owns"},
+		},
+	}
+	query := &Query{
+		Nodes: Nodes{
+			Filters: []Filter{
+				{Condition: Condition{Field: "id", Operator: "in", Values: []string{"web", "platform"}}},
+			},
+		},
+		Boundary: Boundary{Mode: BoundaryAncestor},
+	}
+
+	result, err := ExecuteQuery(query, config)
+	if err != nil {
+		t.Fatalf("ExecuteQuery returned error: %v", err)
+	}
+
+	// The links to the children collapse into the existing link and the link
+	// from the parent to its own child is dropped
+	if len(result.Links) != 1 {
+		t.Fatalf("Expected 1 link, got %v", result.Links)
+	}
+	if link := result.Links[0]; link.Source != "web" || link.Target != "platform" || link.Type != "REST" {
+		t.Errorf("Expected link 'web' -> 'platform', got '%s' -> '%s'", link.Source, link.Target)
+	}
+	for _, node := range result.Nodes {
+		if node.External {
+			t.Errorf("Expected no external stubs, got '%s'", node.ID)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+)
+
+const (
+	// DirectionOut follows links from source to target
+	DirectionOut = "out"
+	// DirectionIn follows links from target to source
+	DirectionIn = "in"
+	// DirectionBoth follows links regardless of their direction
+	DirectionBoth = "both"
+)
+
+// Reachable returns the IDs of all nodes reachable from the start node by
+// following links in the given direction mapped to their distance in hops.
+// Only links with a type in linkTypes are followed unless linkTypes is empty,
+// and a depth of 0 means the traversal is not limited. The start node itself is
+// never included in the result.
+func (ctx *ConfigContext) Reachable(start string, direction string, linkTypes []string, depth int) map[string]int {
+	cacheKey := fmt.Sprintf("%s|%s|%s|%d", start, direction, strings.Join(linkTypes, ","), depth)
+	if cached, exists := ctx.reachableCache[cacheKey]; exists {
+		return cached
+	}
+
+	distances := make(map[string]int)
+	visited := map[string]bool{start: true}
+	frontier := []string{start}
+
+	// Breadth-first traversal so each node is recorded with its shortest distance
+	for hop := 1; len(frontier) > 0 && (depth == 0 || hop <= depth); hop++ {
+		next := []string{}
+		for _, current := range frontier {
+			for _, neighbor := range ctx.neighbors(current, direction, linkTypes) {
+				if visited[neighbor] {
+					continue
+				}
+				visited[neighbor] = true
+				distances[neighbor] = hop
+				next = append(next, neighbor)
+			}
+		}
+		frontier = next
+	}
+
+	ctx.reachableCache[cacheKey] = distances
+	return distances
+}
+
+// neighbors returns the IDs of the nodes one link away from the node in the
+// given direction, following only links with a type in linkTypes if set.
+func (ctx *ConfigContext) neighbors(nodeID string, direction string, linkTypes []string) []string {
+	result := []string{}
+
+	if direction == DirectionOut || direction == DirectionBoth {
+		for _, link := range ctx.OutboundLinks[nodeID] {
+			if LinkTypeAllowed(link, linkTypes) {
+				result = append(result, link.Target)
+			}
+		}
+	}
+
+	if direction == DirectionIn || direction == DirectionBoth {
+		for _, link := range ctx.InboundLinks[nodeID] {
+			if LinkTypeAllowed(link, linkTypes) {
+				result = append(result, link.Source)
+			}
+		}
+	}
+
+	return result
+}
+
+// LinkTypeAllowed checks if the link type is in the list of allowed types.
+// An empty list allows all link types.
+func LinkTypeAllowed(link *configuration.Link, linkTypes []string) bool {
+	return len(linkTypes) == 0 || slices.Contains(linkTypes, link.Type)
+}
+
+// isReachable checks if nodeID can be reached from targetNodeID by following
+// links in the given direction in the given configuration context.
+func isReachable(nodeID string, targetNodeID string, direction string, linkTypes []string, depth int, ctx *ConfigContext) (bool, error) {
+	// Find the target node
+	_, exists := ctx.NodesById[targetNodeID]
+	if !exists {
+		return false, fmt.Errorf("target node with ID %s not found", targetNodeID)
+	}
+
+	_, reachable := ctx.Reachable(targetNodeID, direction, linkTypes, depth)[nodeID]
+	return reachable, nil
+}
@@ -0,0 +1,110 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+)
+
+// compiledRegex returns the regular expression for the 'matches' operator. The
+// expression is compiled once by compileRegexes before the query is executed so
+// the copies of the condition made while matching share it, but is compiled on
+// demand for conditions that were not prepared.
+func (condition *Condition) compiledRegex() (*regexp.Regexp, error) {
+	if condition.regex != nil {
+		return condition.regex, nil
+	}
+
+	re, err := regexp.Compile(condition.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression '%s' for operator 'matches': %v", condition.Value, err)
+	}
+
+	condition.regex = re
+	return re, nil
+}
+
+// compileRegexes compiles the regular expression of every 'matches' condition
+// in the filters, including the nested conditions, in place.
+func compileRegexes(filters []Filter) error {
+	for i := range filters {
+		if err := filters[i].Condition.compileRegexes(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compileRegexes compiles the regular expression of the condition and of its
+// nested conditions in place.
+func (condition *Condition) compileRegexes() error {
+	if condition.Operator == "matches" {
+		if _, err := condition.compiledRegex(); err != nil {
+			return err
+		}
+	}
+	for i := range condition.Conditions {
+		if err := condition.Conditions[i].compileRegexes(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// valueMatchesString checks if a field value satisfies a string operator against
+// the query value. Lists match if any item satisfies the operator.
+func valueMatchesString(fieldValue any, operator string, value string) bool {
+	switch v := fieldValue.(type) {
+	case nil:
+		return false
+	case []any:
+		for _, item := range v {
+			if valueMatchesString(item, operator, value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	str := configuration.FormatAttributeValue(fieldValue)
+	switch operator {
+	case "contains":
+		return strings.Contains(str, value)
+	case "startsWith":
+		return strings.HasPrefix(str, value)
+	case "endsWith":
+		return strings.HasSuffix(str, value)
+	default:
+		return false
+	}
+}
+
+// valueMatchesRegex checks if a field value matches the regular expression.
+// Lists match if any item matches.
+func valueMatchesRegex(fieldValue any, re *regexp.Regexp) bool {
+	switch v := fieldValue.(type) {
+	case nil:
+		return false
+	case []any:
+		for _, item := range v {
+			if valueMatchesRegex(item, re) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return re.MatchString(configuration.FormatAttributeValue(fieldValue))
+}
+
+// valueIn checks if a field value equals any of the provided values.
+func valueIn(fieldValue any, values []string) bool {
+	for _, value := range values {
+		if valueEquals(fieldValue, value) {
+			return true
+		}
+	}
+	return false
+}
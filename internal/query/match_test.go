@@ -0,0 +1,46 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+)
+
+func TestExecuteQueryCompilesRegexOnce(t *testing.T) {
+	query := &Query{
+		Nodes: Nodes{
+			Filters: []Filter{
+				{Condition: Condition{Operator: "and", Conditions: []Condition{
+					{Field: "id", Operator: "matches", Value: "^api"},
+				}}},
+			},
+		},
+	}
+	config := &configuration.Config{
+		Nodes: []configuration.Node{
+			{ID: "api", Type: "service"},
+			{ID: "web", Type: "service"},
+		},
+	}
+
+	// The query is executed without being validated first
+	result, err := ExecuteQuery(query, config)
+	if err != nil {
+		t.Fatalf("ExecuteQuery returned error: %v", err)
+	}
+	if len(result.Nodes) != 1 || result.Nodes[0].ID != "api" {
+		t.Fatalf("Expected only node 'api', got %v", result.Nodes)
+	}
+
+	re := query.Nodes.Filters[0].Condition.Conditions[0].regex
+	if re == nil {
+		t.Fatalf("Expected the nested regular expression to be compiled")
+	}
+
+	if _, err := ExecuteQuery(query, config); err != nil {
+		t.Fatalf("ExecuteQuery returned error: %v", err)
+	}
+	if query.Nodes.Filters[0].Condition.Conditions[0].regex != re {
+		t.Errorf("Expected the compiled regular expression to be reused")
+	}
+}
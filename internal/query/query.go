@@ -2,13 +2,17 @@ package query
 
 import (
 	"fmt"
+	"regexp"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Query struct {
-	Nodes Nodes `yaml:"nodes"`
-	Links Links `yaml:"links"`
+	Nodes     Nodes     `yaml:"nodes"`
+	Links     Links     `yaml:"links"`
+	Expand    Expand    `yaml:"expand,omitempty"`
+	Hierarchy Hierarchy `yaml:"hierarchy,omitempty"`
+	Boundary  Boundary  `yaml:"boundary,omitempty"`
 }
 
 type Nodes struct {
@@ -19,6 +23,30 @@ type Links struct {
 	Filters []Filter `yaml:"filters"`
 }
 
+// Expand pulls the neighbors of the matched nodes into the query result
+type Expand struct {
+	// The number of links to follow from each matched node
+	Depth int `yaml:"depth"`
+	// The direction to follow links (in, out, both)
+	Direction string `yaml:"direction,omitempty"`
+	// The link types to follow, if empty all link types are followed
+	LinkTypes []string `yaml:"linkTypes,omitempty"`
+}
+
+// Hierarchy controls how the parent hierarchy is preserved in the query result
+type Hierarchy struct {
+	// Re-parent nodes to their nearest ancestor in the result instead of removing the parent
+	Reparent bool `yaml:"reparent,omitempty"`
+	// Include all ancestors of the selected nodes in the result
+	IncludeAncestors bool `yaml:"includeAncestors,omitempty"`
+}
+
+// Boundary controls how links that leave the query result are handled
+type Boundary struct {
+	// The mode for links with one endpoint outside of the result (stub, ancestor)
+	Mode string `yaml:"mode,omitempty"`
+}
+
 type Filter struct {
 	Condition Condition `yaml:"condition"`
 }
@@ -27,7 +55,21 @@ type Condition struct {
 	Field      string      `yaml:"field"`
 	Operator   string      `yaml:"operator"`
 	Value      string      `yaml:"value"`
+	Values     []string    `yaml:"values,omitempty"`
+	LinkTypes  []string    `yaml:"linkTypes,omitempty"`
+	Depth      int         `yaml:"depth,omitempty"`
 	Conditions []Condition `yaml:"conditions"`
+
+	// regex is the compiled expression for the 'matches' operator
+	regex *regexp.Regexp
+}
+
+// IsEmpty returns true if the query sets no filters and none of the expand,
+// hierarchy or boundary settings, such a query selects the whole configuration.
+func (q *Query) IsEmpty() bool {
+	return len(q.Nodes.Filters) == 0 && len(q.Links.Filters) == 0 &&
+		q.Expand.Depth == 0 && q.Expand.Direction == "" && len(q.Expand.LinkTypes) == 0 &&
+		q.Hierarchy == (Hierarchy{}) && q.Boundary == (Boundary{})
 }
 
 // YamlString returns the YAML representation of the query
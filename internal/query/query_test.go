@@ -76,7 +76,7 @@ func TestGenerateQuery(t *testing.T) {
 				expectedOutput := string(expectedBytes)
 
 				if outputYaml != expectedOutput {
-					t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, output)
+					t.Errorf("Expected output:\n%s\nGot:\n%s", expectedOutput, outputYaml)
 				}
 			})
 		}
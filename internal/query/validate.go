@@ -11,44 +11,73 @@ const (
 	LinkCondition = 2
 )
 
-// Validate checks if the query is valid.
+// Validate checks if the query is valid, returning all of the problems found
+// as common.ValidationErrors.
 func (q *Query) Validate() error {
+	var errs common.ValidationErrors
 
 	// Validate the nodes
-	err := q.Nodes.Validate()
-	if err != nil {
-		return err
-	}
+	errs.Merge("condition", "nodes", q.Nodes.Validate())
 
 	// Validate the links
-	err = q.Links.validate()
-	if err != nil {
-		return err
-	}
+	errs.Merge("condition", "links", q.Links.validate())
 
-	return nil
+	// Validate the expand settings
+	errs.Merge("expand", "expand", q.Expand.validate())
+
+	// Validate the boundary settings
+	errs.Merge("boundary", "boundary.mode", q.Boundary.validate())
+
+	return errs.ErrOrNil()
 }
 
 // Validate checks if the nodes are valid.
 func (nodes *Nodes) Validate() error {
+	var errs common.ValidationErrors
 
 	// Validate the filters
-	for _, filter := range nodes.Filters {
-		err := filter.Validate(NodeCondition)
-		if err != nil {
-			return err
-		}
+	for i := range nodes.Filters {
+		errs.Merge("condition", fmt.Sprintf("filters[%d]", i), nodes.Filters[i].Validate(NodeCondition))
 	}
 
-	return nil
+	return errs.ErrOrNil()
 }
 
 // Validate checks if the links are valid.
 func (links *Links) validate() error {
+	var errs common.ValidationErrors
 
 	// Validate the filters
-	for _, filter := range links.Filters {
-		err := filter.Validate(LinkCondition)
+	for i := range links.Filters {
+		errs.Merge("condition", fmt.Sprintf("filters[%d]", i), links.Filters[i].Validate(LinkCondition))
+	}
+
+	return errs.ErrOrNil()
+}
+
+// validate checks if the expand settings are valid.
+func (expand *Expand) validate() error {
+
+	// Expand is optional, but once configured the depth must be set
+	if expand.Depth == 0 && expand.Direction == "" && len(expand.LinkTypes) == 0 {
+		return nil
+	}
+
+	if expand.Depth <= 0 {
+		return fmt.Errorf("'expand.depth' must be greater than 0")
+	}
+
+	switch expand.Direction {
+	case "":
+	case DirectionIn:
+	case DirectionOut:
+	case DirectionBoth:
+	default:
+		return fmt.Errorf("invalid expand direction: '%s'", expand.Direction)
+	}
+
+	for _, linkType := range expand.LinkTypes {
+		err := common.IsValidName(linkType, "expand.linkTypes")
 		if err != nil {
 			return err
 		}
@@ -57,19 +86,34 @@ func (links *Links) validate() error {
 	return nil
 }
 
+// validate checks if the boundary settings are valid.
+func (boundary *Boundary) validate() error {
+
+	// Validate the mode is valid
+	switch boundary.Mode {
+	case "":
+	case BoundaryStub:
+	case BoundaryAncestor:
+	default:
+		return fmt.Errorf("invalid boundary mode: '%s'", boundary.Mode)
+	}
+
+	return nil
+}
+
 // Validate checks if the filter is valid.
 func (filter *Filter) Validate(filterType int) error {
 
+	var errs common.ValidationErrors
+
 	// Validate the condition
-	err := filter.Condition.validate(filterType)
-	if err != nil {
-		return err
-	}
+	errs.Merge("condition", "condition", filter.Condition.validate(filterType))
 
-	return nil
+	return errs.ErrOrNil()
 }
 
-// Validate checks if the condition is valid.
+// validate checks if the condition is valid. Problems with the condition
+// itself stop at the first one found, but every nested condition is validated.
 func (condition *Condition) validate(filterType int) error {
 	var err error
 
@@ -90,9 +134,25 @@ func (condition *Condition) validate(filterType int) error {
 	allowValue := false
 	requireValue := false
 
+	allowValues := false
+	requireValues := false
+
 	allowCondition := false
 	requireCondition := false
 
+	// Ordering operators require values that are numbers or versions
+	requireComparable := false
+
+	// The 'matches' operator requires the value to be a valid regular expression
+	requireRegex := false
+
+	// Link traversal operators allow restricting the link types and depth
+	allowLinkTypes := false
+	allowDepth := false
+
+	// Relationship operators on links apply to the node at the 'source' or 'target' end
+	endpointField := false
+
 	// Validate the operation is 'equals' using a switch so it is easy to add more operations later
 	switch condition.Operator {
 	case "equals":
@@ -105,6 +165,67 @@ func (condition *Condition) validate(filterType int) error {
 		requireField = true
 		requireValue = true
 
+	case "greaterThan":
+		allowCommand = true
+		requireField = true
+		requireValue = true
+		requireComparable = true
+
+	case "lessThan":
+		allowCommand = true
+		requireField = true
+		requireValue = true
+		requireComparable = true
+
+	case "greaterOrEqual":
+		allowCommand = true
+		requireField = true
+		requireValue = true
+		requireComparable = true
+
+	case "lessOrEqual":
+		allowCommand = true
+		requireField = true
+		requireValue = true
+		requireComparable = true
+
+	case "between":
+		allowCommand = true
+		requireField = true
+		requireValues = true
+		requireComparable = true
+
+	case "contains":
+		allowCommand = true
+		requireField = true
+		requireValue = true
+
+	case "startsWith":
+		allowCommand = true
+		requireField = true
+		requireValue = true
+
+	case "endsWith":
+		allowCommand = true
+		requireField = true
+		requireValue = true
+
+	case "matches":
+		allowCommand = true
+		requireField = true
+		requireValue = true
+		requireRegex = true
+
+	case "in":
+		allowCommand = true
+		requireField = true
+		requireValues = true
+
+	case "notIn":
+		allowCommand = true
+		requireField = true
+		requireValues = true
+
 	case "exists":
 		allowCommand = true
 		requireField = true
@@ -117,27 +238,72 @@ func (condition *Condition) validate(filterType int) error {
 		allowCommand = true
 		requireCondition = true
 
+	case "not":
+		allowCommand = true
+		requireCondition = true
+
+	case "downstreamOf":
+		allowCommand = true
+		requireValue = true
+		endpointField = filterType == LinkCondition
+		allowLinkTypes = true
+		allowDepth = true
+
+	case "upstreamOf":
+		allowCommand = true
+		requireValue = true
+		endpointField = filterType == LinkCondition
+		allowLinkTypes = true
+		allowDepth = true
+
+	case "connectedTo":
+		allowCommand = true
+		requireValue = true
+		endpointField = filterType == LinkCondition
+		allowLinkTypes = true
+		allowDepth = true
+
+	case "directlyCalls":
+		allowCommand = true
+		requireValue = true
+		endpointField = filterType == LinkCondition
+		allowLinkTypes = true
+
+	case "calledBy":
+		allowCommand = true
+		requireValue = true
+		endpointField = filterType == LinkCondition
+		allowLinkTypes = true
+
 	case "ancestorOf":
-		allowCommand = filterType == NodeCondition
+		allowCommand = true
 		requireValue = true
+		endpointField = filterType == LinkCondition
 
 	case "descendantOf":
-		allowCommand = filterType == NodeCondition
+		allowCommand = true
 		requireValue = true
+		endpointField = filterType == LinkCondition
 
 	case "childOf":
-		allowCommand = filterType == NodeCondition
+		allowCommand = true
 		requireValue = true
+		endpointField = filterType == LinkCondition
 
 	case "parentOf":
-		allowCommand = filterType == NodeCondition
+		allowCommand = true
 		requireValue = true
+		endpointField = filterType == LinkCondition
 
 	default:
 		return fmt.Errorf("invalid operator: '%s'", condition.Operator)
 	}
 
 	// Set the allow and require flags based on the filter type
+	if endpointField {
+		requireField = true
+	}
+
 	if requireValue {
 		allowValue = true
 	}
@@ -146,6 +312,10 @@ func (condition *Condition) validate(filterType int) error {
 		allowField = true
 	}
 
+	if requireValues {
+		allowValues = true
+	}
+
 	if requireCondition {
 		allowCondition = true
 	}
@@ -156,40 +326,16 @@ func (condition *Condition) validate(filterType int) error {
 		return fmt.Errorf("'field' property is required for operator '%s'", condition.Operator)
 	} else if !allowField && condition.Field != "" {
 		return fmt.Errorf("'field' property is not allowed for operator '%s'", condition.Operator)
+	} else if allowField && endpointField {
+		// Validate the field selects the endpoint of the link
+		if condition.Field != "source" && condition.Field != "target" {
+			return fmt.Errorf("field '%s' is not allowed for operator '%s', must be 'source' or 'target'", condition.Field, condition.Operator)
+		}
 	} else if allowField {
 		// Validate the field
-		field := condition.Field
-		switch field {
-		case "type":
-			// Allowed for everything
-		case "id":
-			if filterType != NodeCondition {
-				return fmt.Errorf("field '%s' is not allowed for %s", field, conditionType)
-			}
-		case "parent":
-			if filterType != NodeCondition {
-				return fmt.Errorf("field '%s' is not allowed for %s", field, conditionType)
-			}
-		case "source":
-			if filterType != LinkCondition {
-				return fmt.Errorf("field '%s' is not allowed for %s", field, conditionType)
-			}
-		case "target":
-			if filterType != LinkCondition {
-				return fmt.Errorf("field '%s' is not allowed for %s", field, conditionType)
-			}
-		default:
-
-			// Check if key starts with 'attribute.'
-			if len(field) > 10 && field[:10] == "attribute." {
-				// Validate the attribute key
-				err = common.IsValidName(field[10:], "attribute.key")
-				if err != nil {
-					return err
-				}
-			} else {
-				return fmt.Errorf("invalid field: '%s'", field)
-			}
+		err = validateField(condition.Field, filterType)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -203,6 +349,59 @@ func (condition *Condition) validate(filterType int) error {
 		if err != nil {
 			return err
 		}
+
+		if requireComparable && !isComparableValue(condition.Value) {
+			return fmt.Errorf("value '%s' for operator '%s' must be a number or version", condition.Value, condition.Operator)
+		}
+
+		if requireRegex {
+			// Compile the regular expression once so it is reused when the query is executed
+			condition.regex = nil
+			_, err = condition.compiledRegex()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if requireValues && len(condition.Values) == 0 {
+		return fmt.Errorf("'values' property is required for operator '%s'", condition.Operator)
+	} else if !allowValues && len(condition.Values) > 0 {
+		return fmt.Errorf("'values' property is not allowed for operator '%s'", condition.Operator)
+	} else if allowValues {
+		// Validate the values
+		for _, value := range condition.Values {
+			err = common.IsValidValue(value, "values")
+			if err != nil {
+				return err
+			}
+
+			if requireComparable && !isComparableValue(value) {
+				return fmt.Errorf("value '%s' for operator '%s' must be a number or version", value, condition.Operator)
+			}
+		}
+
+		if condition.Operator == "between" && len(condition.Values) != 2 {
+			return fmt.Errorf("operator 'between' requires exactly 2 'values'")
+		}
+	}
+
+	if !allowLinkTypes && len(condition.LinkTypes) > 0 {
+		return fmt.Errorf("'linkTypes' property is not allowed for operator '%s'", condition.Operator)
+	} else if allowLinkTypes {
+		// Validate the link types
+		for _, linkType := range condition.LinkTypes {
+			err = common.IsValidName(linkType, "linkTypes")
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if !allowDepth && condition.Depth != 0 {
+		return fmt.Errorf("'depth' property is not allowed for operator '%s'", condition.Operator)
+	} else if condition.Depth < 0 {
+		return fmt.Errorf("'depth' property cannot be negative for operator '%s'", condition.Operator)
 	}
 
 	if requireCondition && len(condition.Conditions) == 0 {
@@ -210,13 +409,68 @@ func (condition *Condition) validate(filterType int) error {
 	} else if !allowCondition && len(condition.Conditions) > 0 {
 		return fmt.Errorf("'conditions' property is not allowed for operator '%s'", condition.Operator)
 	} else if allowCondition {
+		var errs common.ValidationErrors
+
+		if condition.Operator == "not" && len(condition.Conditions) != 1 {
+			errs.Add("condition", "conditions", fmt.Errorf("operator 'not' requires exactly 1 condition"))
+		}
+
+		// Validate every nested condition so all of the problems are reported
+		for i := range condition.Conditions {
+			errs.Merge("condition", fmt.Sprintf("conditions[%d]", i), condition.Conditions[i].validate(filterType))
+		}
+
+		return errs.ErrOrNil()
+	}
+
+	return nil
+}
+
+// validateField checks if the field can be used in a condition for the filter type.
+func validateField(field string, filterType int) error {
+	conditionType := "nodes"
+	if filterType == LinkCondition {
+		conditionType = "links"
+	}
+
+	switch field {
+	case "type":
+		// Allowed for everything
+	case "id":
+		if filterType != NodeCondition {
+			return fmt.Errorf("field '%s' is not allowed for %s", field, conditionType)
+		}
+	case "parent":
+		if filterType != NodeCondition {
+			return fmt.Errorf("field '%s' is not allowed for %s", field, conditionType)
+		}
+	case "source":
+		if filterType != LinkCondition {
+			return fmt.Errorf("field '%s' is not allowed for %s", field, conditionType)
+		}
+	case "target":
+		if filterType != LinkCondition {
+			return fmt.Errorf("field '%s' is not allowed for %s", field, conditionType)
+		}
+	default:
+
+		// Links can reference the fields of the source and target nodes such as 'source.type'
+		if _, nodeField, ok := splitEndpointField(field); ok && filterType == LinkCondition {
+			if validateField(nodeField, NodeCondition) != nil {
+				return fmt.Errorf("invalid field: '%s'", field)
+			}
+			return nil
+		}
 
-		// Validate the conditions
-		for _, subCondition := range condition.Conditions {
-			err = subCondition.validate(filterType)
+		// Check if key starts with 'attribute.'
+		if len(field) > 10 && field[:10] == "attribute." {
+			// Validate the attribute key
+			err := common.IsValidName(field[10:], "attribute.key")
 			if err != nil {
 				return err
 			}
+		} else {
+			return fmt.Errorf("invalid field: '%s'", field)
 		}
 	}
 
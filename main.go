@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"regexp"
 	"runtime"
 	"runtime/debug"
@@ -12,9 +13,15 @@ import (
 
 	"golang.org/x/term"
 
+	a "github.com/UnitVectorY-Labs/YAMLtecture/internal/analysis"
+	c4 "github.com/UnitVectorY-Labs/YAMLtecture/internal/c4"
 	"github.com/UnitVectorY-Labs/YAMLtecture/internal/common"
 	c "github.com/UnitVectorY-Labs/YAMLtecture/internal/configuration"
+	d2 "github.com/UnitVectorY-Labs/YAMLtecture/internal/d2"
+	d "github.com/UnitVectorY-Labs/YAMLtecture/internal/dot"
+	l "github.com/UnitVectorY-Labs/YAMLtecture/internal/lint"
 	m "github.com/UnitVectorY-Labs/YAMLtecture/internal/mermaid"
+	p "github.com/UnitVectorY-Labs/YAMLtecture/internal/plantuml"
 	q "github.com/UnitVectorY-Labs/YAMLtecture/internal/query"
 )
 
@@ -24,22 +31,74 @@ var (
 	outFlag = flag.String("out", "", "Output file to write")
 
 	// Explicitly set the query and config files
-	configFlag  = flag.String("configIn", "", "Input file for the Config YAML architecture file")
-	queryFlag   = flag.String("queryIn", "", "Input file for the Query YAML architecture file")
-	mermaidFlag = flag.String("mermaidIn", "", "Input file for the Mermaid settings")
+	configFlag        = flag.String("configIn", "", "Input file for the Config YAML architecture file")
+	queryFlag         = flag.String("queryIn", "", "Input file for the Query YAML architecture file")
+	mermaidFlag       = flag.String("mermaidIn", "", "Input file for the Mermaid settings")
+	lintInFlag        = flag.String("lintIn", "", "Input file for the Lint YAML policy rules")
+	dotInFlag         = flag.String("dotIn", "", "Input file for the Graphviz DOT settings")
+	plantumlInFlag    = flag.String("plantumlIn", "", "Input file for the PlantUML settings")
+	c4InFlag          = flag.String("c4In", "", "Input file for the C4 view settings")
+	structurizrInFlag = flag.String("structurizrIn", "", "Input file for the Structurizr workspace settings")
+	d2InFlag          = flag.String("d2In", "", "Input file for the D2 settings")
 
 	// The various commands to run
-	validateConfigFlag  = flag.Bool("validateConfig", false, "Validate the Config YAML architecture file")
-	validateQueryFlag   = flag.Bool("validateQuery", false, "Validate the Query YAML architecture file")
-	validateMermaidFlag = flag.Bool("validateMermaid", false, "Validate the Mermaid settings")
-	mergeConfigFlag     = flag.Bool("mergeConfig", false, "Merge the Config YAML architecture file")
-	executeQueryFlag    = flag.Bool("executeQuery", false, "Execute the Query YAML architecture file")
-	generateMermaidFlag = flag.Bool("generateMermaid", false, "Generate a Mermaid diagram from the Config YAML architecture file")
+	validateConfigFlag      = flag.Bool("validateConfig", false, "Validate the Config YAML architecture file")
+	validateQueryFlag       = flag.Bool("validateQuery", false, "Validate the Query YAML architecture file")
+	validateMermaidFlag     = flag.Bool("validateMermaid", false, "Validate the Mermaid settings")
+	mergeConfigFlag         = flag.Bool("mergeConfig", false, "Merge the Config YAML architecture file")
+	executeQueryFlag        = flag.Bool("executeQuery", false, "Execute the Query YAML architecture file")
+	generateMermaidFlag     = flag.Bool("generateMermaid", false, "Generate a Mermaid diagram from the Config YAML architecture file")
+	validateLintFlag        = flag.Bool("validateLint", false, "Validate the Lint YAML policy rules")
+	lintFlag                = flag.Bool("lint", false, "Evaluate the Lint YAML policy rules against the Config YAML architecture file")
+	detectCyclesFlag        = flag.Bool("detectCycles", false, "Detect the dependency cycles in the links of the Config YAML architecture file")
+	analyzeFlag             = flag.Bool("analyze", false, "Compute the graph metrics of the Config YAML architecture file")
+	validateDotFlag         = flag.Bool("validateDot", false, "Validate the Graphviz DOT settings")
+	generateDotFlag         = flag.Bool("generateDot", false, "Generate a Graphviz DOT diagram from the Config YAML architecture file")
+	validatePlantUMLFlag    = flag.Bool("validatePlantUML", false, "Validate the PlantUML settings")
+	generatePlantUMLFlag    = flag.Bool("generatePlantUML", false, "Generate a PlantUML diagram from the Config YAML architecture file")
+	validateC4Flag          = flag.Bool("validateC4", false, "Validate the C4 view settings")
+	generateC4Flag          = flag.Bool("generateC4", false, "Generate a Mermaid C4 diagram from the Config YAML architecture file")
+	validateStructurizrFlag = flag.Bool("validateStructurizr", false, "Validate the Structurizr workspace settings")
+	generateStructurizrFlag = flag.Bool("generateStructurizr", false, "Generate a Structurizr workspace DSL from the Config YAML architecture file")
+	validateD2Flag          = flag.Bool("validateD2", false, "Validate the D2 settings")
+	generateD2Flag          = flag.Bool("generateD2", false, "Generate a D2 diagram from the Config YAML architecture file")
+
+	// Folder loading options
+	includeFlag             stringListFlag
+	excludeFlag             stringListFlag
+	namespaceFromFolderFlag = flag.Bool("namespaceFromFolder", false, "Use the subfolder path as the namespace for files loaded from a folder that do not declare one")
+
+	// Analysis options
+	linkTypesFlag stringListFlag
+	formatFlag    = flag.String("format", "yaml", "Format of the analysis report, either 'yaml' or 'json'")
 
 	// Modifiers
-	debugFlag = flag.Bool("debug", false, "Enable debug output")
+	debugFlag       = flag.Bool("debug", false, "Enable debug output")
+	errorFormatFlag = flag.String("errorFormat", "text", "Format of the validation errors, either 'text' or 'json'")
 )
 
+// stringListFlag is a flag that can be repeated or set to a comma separated list
+type stringListFlag []string
+
+func (f *stringListFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringListFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*f = append(*f, item)
+		}
+	}
+	return nil
+}
+
+func init() {
+	flag.Var(&includeFlag, "include", "Glob pattern for the files to load from a folder, can be repeated")
+	flag.Var(&excludeFlag, "exclude", "Glob pattern for the files and folders to skip when loading a folder, can be repeated")
+	flag.Var(&linkTypesFlag, "linkTypes", "Link type to follow when analyzing the links, can be repeated, all link types if not set")
+}
+
 var Version = "dev" // This will be set by the build systems to the release version
 
 var semverRe = regexp.MustCompile(`^\d+\.\d+\.\d+`)
@@ -71,8 +130,18 @@ func main() {
 		return
 	}
 
+	// Validate the error format
+	if *errorFormatFlag != "text" && *errorFormatFlag != "json" {
+		common.PrintError(fmt.Sprintf("Invalid error format: '%s'", *errorFormatFlag), nil)
+	}
+
+	// Validate the output format
+	if *formatFlag != "yaml" && *formatFlag != "json" {
+		common.PrintError(fmt.Sprintf("Invalid format: '%s'", *formatFlag), nil)
+	}
+
 	// First determine what we are doing
-	checkMultipleCommands(*validateConfigFlag, *validateQueryFlag, *validateMermaidFlag, *mergeConfigFlag, *executeQueryFlag, *generateMermaidFlag)
+	checkMultipleCommands(*validateConfigFlag, *validateQueryFlag, *validateMermaidFlag, *mergeConfigFlag, *executeQueryFlag, *generateMermaidFlag, *validateLintFlag, *lintFlag, *detectCyclesFlag, *analyzeFlag, *validateDotFlag, *generateDotFlag, *validatePlantUMLFlag, *generatePlantUMLFlag, *validateC4Flag, *generateC4Flag, *validateStructurizrFlag, *generateStructurizrFlag, *validateD2Flag, *generateD2Flag)
 
 	if *validateConfigFlag {
 		// Validate the config file
@@ -80,12 +149,17 @@ func main() {
 
 		config, err := c.ParseYAML(content)
 		if err != nil {
-			common.PrintError("Error parsing YAML", err)
+			printValidationError("Error parsing YAML", "config", err)
+		}
+
+		err = config.ResolveImports(inputPath(*configFlag, *inFlag))
+		if err != nil {
+			printValidationError("Error resolving imports", "config", err)
 		}
 
 		err = config.Validate()
 		if err != nil {
-			common.PrintError("Error validating configuration", err)
+			printValidationError("Error validating configuration", "config", err)
 		}
 
 	} else if *validateQueryFlag {
@@ -94,12 +168,12 @@ func main() {
 
 		query, err := q.ParseQuery(content)
 		if err != nil {
-			common.PrintError("Error loading query", err)
+			printValidationError("Error loading query", "query", err)
 		}
 
 		err = query.Validate()
 		if err != nil {
-			common.PrintError("Error validating query", err)
+			printValidationError("Error validating query", "query", err)
 		}
 
 	} else if *mergeConfigFlag {
@@ -108,14 +182,14 @@ func main() {
 			common.PrintError("No input folder specified", nil)
 		}
 
-		config, err := c.LoadFolder(*inFlag)
+		config, err := c.LoadFolderWithOptions(*inFlag, loadOptions())
 		if err != nil {
-			common.PrintError("Error loading folder", err)
+			printValidationError("Error loading folder", "config", err)
 		}
 
 		err = config.Validate()
 		if err != nil {
-			common.PrintError("Error validating configuration", err)
+			printValidationError("Error validating configuration", "config", err)
 		}
 
 		writeOutput(config.YamlString(), *outFlag)
@@ -126,37 +200,32 @@ func main() {
 
 		mermaid, err := m.ParseYAML(content)
 		if err != nil {
-			common.PrintError("Error parsing YAML", err)
+			printValidationError("Error parsing YAML", "mermaid", err)
 		}
 
 		err = mermaid.Validate()
 		if err != nil {
-			common.PrintError("Error validating mermaid", err)
+			printValidationError("Error validating mermaid", "mermaid", err)
 		}
 
 	} else if *executeQueryFlag {
 		// Execute the query file
-		configContent := readFileContent(*configFlag, false, *inFlag, true, "")
+		config := readConfig(*configFlag)
 		queryContent := readFileContent(*queryFlag, false, *inFlag, false, "")
 
-		config, err := c.ParseYAML(configContent)
-		if err != nil {
-			common.PrintError("Error parsing YAML", err)
-		}
-
-		err = config.Validate()
+		err := config.Validate()
 		if err != nil {
-			common.PrintError("Error validating configuration", err)
+			printValidationError("Error validating configuration", "config", err)
 		}
 
 		query, err := q.ParseQuery(queryContent)
 		if err != nil {
-			common.PrintError("Error loading query", err)
+			printValidationError("Error loading query", "query", err)
 		}
 
 		err = query.Validate()
 		if err != nil {
-			common.PrintError("Error validating query", err)
+			printValidationError("Error validating query", "query", err)
 		}
 
 		result, err := q.ExecuteQuery(query, config)
@@ -168,27 +237,22 @@ func main() {
 
 	} else if *generateMermaidFlag {
 		// Generate the Mermaid diagram
-		queryContent := readFileContent(*configFlag, false, *inFlag, true, "")
+		config := readConfig(*configFlag)
 		mermaidContent := readFileContent(*mermaidFlag, false, *inFlag, false, "\n")
 
-		config, err := c.ParseYAML(queryContent)
-		if err != nil {
-			common.PrintError("Error parsing YAML", err)
-		}
-
-		err = config.Validate()
+		err := config.Validate()
 		if err != nil {
-			common.PrintError("Error validating configuration", err)
+			printValidationError("Error validating configuration", "config", err)
 		}
 
 		mermaid, err := m.ParseYAML(mermaidContent)
 		if err != nil {
-			common.PrintError("Error parsing YAML", err)
+			printValidationError("Error parsing YAML", "mermaid", err)
 		}
 
 		err = mermaid.Validate()
 		if err != nil {
-			common.PrintError("Error validating mermaid", err)
+			printValidationError("Error validating mermaid", "mermaid", err)
 		}
 
 		mermaidDiagram, err := m.GenerateMermaid(config, mermaid)
@@ -198,12 +262,321 @@ func main() {
 
 		writeOutput(mermaidDiagram, *outFlag)
 
+	} else if *validateLintFlag {
+		// Validate the lint file
+		content := readFileContent(*lintInFlag, true, *inFlag, true, "")
+
+		lint, err := l.ParseYAML(content)
+		if err != nil {
+			printValidationError("Error parsing YAML", "lint", err)
+		}
+
+		err = lint.Validate()
+		if err != nil {
+			printValidationError("Error validating lint", "lint", err)
+		}
+
+	} else if *lintFlag {
+		// Evaluate the lint rules against the config
+		config := readConfig(*configFlag)
+		lintContent := readFileContent(*lintInFlag, false, *inFlag, false, "")
+
+		err := config.Validate()
+		if err != nil {
+			printValidationError("Error validating configuration", "config", err)
+		}
+
+		lint, err := l.ParseYAML(lintContent)
+		if err != nil {
+			printValidationError("Error parsing YAML", "lint", err)
+		}
+
+		err = lint.Validate()
+		if err != nil {
+			printValidationError("Error validating lint", "lint", err)
+		}
+
+		result, err := l.Evaluate(lint, config)
+		if err != nil {
+			common.PrintError("Error evaluating lint", err)
+		}
+
+		writeOutput(result.String(), *outFlag)
+
+		// Violations with the error severity fail the command
+		if result.HasErrors() {
+			os.Exit(1)
+		}
+
+	} else if *detectCyclesFlag {
+		// Detect the cycles in the links of the config
+		config := readConfig(inputPath(*configFlag, *inFlag))
+
+		err := config.Validate()
+		if err != nil {
+			printValidationError("Error validating configuration", "config", err)
+		}
+
+		cycles := a.FindCycles(config, linkTypesFlag)
+
+		writeOutput(cycles.String(), *outFlag)
+
+		// Any cycle fails the command
+		if len(cycles) > 0 {
+			os.Exit(1)
+		}
+
+	} else if *analyzeFlag {
+		// Compute the metrics of the config
+		config := readConfig(inputPath(*configFlag, *inFlag))
+
+		err := config.Validate()
+		if err != nil {
+			printValidationError("Error validating configuration", "config", err)
+		}
+
+		report := a.Analyze(config, linkTypesFlag)
+
+		if *formatFlag == "json" {
+			writeOutput(report.JSON(), *outFlag)
+		} else {
+			writeOutput(report.YamlString(), *outFlag)
+		}
+
+	} else if *validateDotFlag {
+		// Validate the dot file
+		content := readFileContent(*dotInFlag, true, *inFlag, true, "")
+
+		dot, err := d.ParseYAML(content)
+		if err != nil {
+			printValidationError("Error parsing YAML", "dot", err)
+		}
+
+		err = dot.Validate()
+		if err != nil {
+			printValidationError("Error validating dot", "dot", err)
+		}
+
+	} else if *generateDotFlag {
+		// Generate the Graphviz DOT diagram
+		config := readConfig(*configFlag)
+		dotContent := readFileContent(*dotInFlag, false, *inFlag, false, "\n")
+
+		err := config.Validate()
+		if err != nil {
+			printValidationError("Error validating configuration", "config", err)
+		}
+
+		dot, err := d.ParseYAML(dotContent)
+		if err != nil {
+			printValidationError("Error parsing YAML", "dot", err)
+		}
+
+		err = dot.Validate()
+		if err != nil {
+			printValidationError("Error validating dot", "dot", err)
+		}
+
+		dotDiagram, err := d.GenerateDot(config, dot)
+		if err != nil {
+			common.PrintError("Error generating DOT diagram", err)
+		}
+
+		writeOutput(dotDiagram, *outFlag)
+
+	} else if *validatePlantUMLFlag {
+		// Validate the plantuml file
+		content := readFileContent(*plantumlInFlag, true, *inFlag, true, "")
+
+		plantuml, err := p.ParseYAML(content)
+		if err != nil {
+			printValidationError("Error parsing YAML", "plantuml", err)
+		}
+
+		err = plantuml.Validate()
+		if err != nil {
+			printValidationError("Error validating plantuml", "plantuml", err)
+		}
+
+	} else if *generatePlantUMLFlag {
+		// Generate the PlantUML diagram
+		config := readConfig(*configFlag)
+		plantumlContent := readFileContent(*plantumlInFlag, false, *inFlag, false, "\n")
+
+		err := config.Validate()
+		if err != nil {
+			printValidationError("Error validating configuration", "config", err)
+		}
+
+		plantuml, err := p.ParseYAML(plantumlContent)
+		if err != nil {
+			printValidationError("Error parsing YAML", "plantuml", err)
+		}
+
+		err = plantuml.Validate()
+		if err != nil {
+			printValidationError("Error validating plantuml", "plantuml", err)
+		}
+
+		plantumlDiagram, err := p.GeneratePlantUML(config, plantuml)
+		if err != nil {
+			common.PrintError("Error generating PlantUML diagram", err)
+		}
+
+		writeOutput(plantumlDiagram, *outFlag)
+
+	} else if *validateC4Flag {
+		// Validate the c4 file
+		content := readFileContent(*c4InFlag, true, *inFlag, true, "")
+
+		view, err := c4.ParseYAML(content)
+		if err != nil {
+			printValidationError("Error parsing YAML", "c4", err)
+		}
+
+		err = view.Validate()
+		if err != nil {
+			printValidationError("Error validating c4", "c4", err)
+		}
+
+	} else if *generateC4Flag {
+		// Generate the Mermaid C4 diagram, the levels must be designated so there are no default settings
+		config := readConfig(*configFlag)
+		c4Content := readFileContent(*c4InFlag, false, *inFlag, false, "")
+
+		err := config.Validate()
+		if err != nil {
+			printValidationError("Error validating configuration", "config", err)
+		}
+
+		view, err := c4.ParseYAML(c4Content)
+		if err != nil {
+			printValidationError("Error parsing YAML", "c4", err)
+		}
+
+		err = view.Validate()
+		if err != nil {
+			printValidationError("Error validating c4", "c4", err)
+		}
+
+		c4Diagram, err := c4.GenerateC4(config, view)
+		if err != nil {
+			common.PrintError("Error generating C4 diagram", err)
+		}
+
+		writeOutput(c4Diagram, *outFlag)
+
+	} else if *validateStructurizrFlag {
+		// Validate the structurizr file
+		content := readFileContent(*structurizrInFlag, true, *inFlag, true, "")
+
+		workspace, err := c4.ParseStructurizrYAML(content)
+		if err != nil {
+			printValidationError("Error parsing YAML", "structurizr", err)
+		}
+
+		// Query files are relative to the settings file, or the working directory for standard input
+		err = workspace.LoadQueries(filepath.Dir(inputPath(*structurizrInFlag, *inFlag)))
+		if err != nil {
+			printValidationError("Error loading query", "structurizr", err)
+		}
+
+		err = workspace.Validate()
+		if err != nil {
+			printValidationError("Error validating structurizr", "structurizr", err)
+		}
+
+	} else if *generateStructurizrFlag {
+		// Generate the Structurizr workspace DSL, the levels must be designated so there are no default settings
+		config := readConfig(*configFlag)
+		structurizrContent := readFileContent(*structurizrInFlag, false, *inFlag, false, "")
+
+		err := config.Validate()
+		if err != nil {
+			printValidationError("Error validating configuration", "config", err)
+		}
+
+		workspace, err := c4.ParseStructurizrYAML(structurizrContent)
+		if err != nil {
+			printValidationError("Error parsing YAML", "structurizr", err)
+		}
+
+		// Query files are relative to the settings file, or the working directory for standard input,
+		// the generic input flag cannot provide the settings when generating
+		err = workspace.LoadQueries(filepath.Dir(*structurizrInFlag))
+		if err != nil {
+			printValidationError("Error loading query", "structurizr", err)
+		}
+
+		err = workspace.Validate()
+		if err != nil {
+			printValidationError("Error validating structurizr", "structurizr", err)
+		}
+
+		dsl, err := c4.GenerateStructurizr(config, workspace)
+		if err != nil {
+			common.PrintError("Error generating Structurizr workspace", err)
+		}
+
+		writeOutput(dsl, *outFlag)
+
+	} else if *validateD2Flag {
+		// Validate the d2 file
+		content := readFileContent(*d2InFlag, true, *inFlag, true, "")
+
+		setting, err := d2.ParseYAML(content)
+		if err != nil {
+			printValidationError("Error parsing YAML", "d2", err)
+		}
+
+		err = setting.Validate()
+		if err != nil {
+			printValidationError("Error validating d2", "d2", err)
+		}
+
+	} else if *generateD2Flag {
+		// Generate the D2 diagram
+		config := readConfig(*configFlag)
+		d2Content := readFileContent(*d2InFlag, false, *inFlag, false, "\n")
+
+		err := config.Validate()
+		if err != nil {
+			printValidationError("Error validating configuration", "config", err)
+		}
+
+		setting, err := d2.ParseYAML(d2Content)
+		if err != nil {
+			printValidationError("Error parsing YAML", "d2", err)
+		}
+
+		err = setting.Validate()
+		if err != nil {
+			printValidationError("Error validating d2", "d2", err)
+		}
+
+		d2Diagram, err := d2.GenerateD2(config, setting)
+		if err != nil {
+			common.PrintError("Error generating D2 diagram", err)
+		}
+
+		writeOutput(d2Diagram, *outFlag)
+
 	} else {
 		// Write error to error output
 		common.PrintError("No command specified", nil)
 	}
 }
 
+// printValidationError prints the validation errors in the format set by the -errorFormat flag and exits.
+// The JSON format is written to STDOUT so it can be consumed by CI tooling.
+func printValidationError(message string, kind string, err error) {
+	if *errorFormatFlag == "json" {
+		fmt.Println(common.AsValidationErrors(kind, err).JSON())
+		os.Exit(1)
+	}
+	common.PrintError(message, err)
+}
+
 // writeOutput will take the string content and if the -out flag is set, write it to the file, if not it writes to STDOUT
 func writeOutput(content string, outFlag string) {
 	if outFlag != "" {
@@ -216,6 +589,50 @@ func writeOutput(content string, outFlag string) {
 	}
 }
 
+// readConfig loads the configuration from the -configIn flag, merging all of the files if it is
+// a folder, or from STDIN if it is not set
+func readConfig(configFlag string) *c.Config {
+	if configFlag != "" {
+		fileInfo, err := os.Stat(configFlag)
+		if err == nil && fileInfo.IsDir() {
+			config, err := c.LoadFolderWithOptions(configFlag, loadOptions())
+			if err != nil {
+				printValidationError("Error loading folder", "config", err)
+			}
+			return config
+		}
+	}
+
+	content := readFileContent(configFlag, false, "", true, "")
+	config, err := c.ParseYAML(content)
+	if err != nil {
+		printValidationError("Error parsing YAML", "config", err)
+	}
+
+	err = config.ResolveImports(configFlag)
+	if err != nil {
+		printValidationError("Error resolving imports", "config", err)
+	}
+	return config
+}
+
+// inputPath returns the path of the file the input is read from, or an empty string for STDIN
+func inputPath(specificFlag string, genericFlag string) string {
+	if specificFlag != "" {
+		return specificFlag
+	}
+	return genericFlag
+}
+
+// loadOptions returns the options for loading a folder based on the flags provided
+func loadOptions() c.LoadOptions {
+	return c.LoadOptions{
+		Include:             includeFlag,
+		Exclude:             excludeFlag,
+		NamespaceFromFolder: *namespaceFromFolderFlag,
+	}
+}
+
 // Read the content of a file based on the flags provided
 func readFileContent(specificFlag string, allowGenericFlag bool, genericFlag string, allowStdin bool, defaultValue string) string {
 	if specificFlag != "" {
@@ -99,6 +99,71 @@ func TestCLICommands(t *testing.T) {
 			expectedExitCode: 1,
 			expectedOutFile:  "",
 		},
+		// Typed attribute values
+		{
+			name: "Typed attributes validate config",
+			args: []string{
+				"-validateConfig",
+				"-configIn=./tests/typed_attributes/config.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "",
+		},
+		{
+			name: "Typed attributes execute query",
+			args: []string{
+				"-executeQuery",
+				"-configIn=./tests/typed_attributes/config.yaml",
+				"-queryIn=./tests/typed_attributes/queries/pci_equals/query.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "./tests/typed_attributes/queries/pci_equals/config.yaml",
+		},
+		// Aggregated validation errors
+		{
+			name: "Validate config multiple errors as JSON",
+			args: []string{
+				"-validateConfig",
+				"-errorFormat=json",
+				"-configIn=./tests/invalid/config/multiple_errors/input.yaml"},
+			expectedExitCode: 1,
+			expectedOutFile:  "./tests/invalid/config/multiple_errors/expected_error.json",
+		},
+		{
+			name: "Validate query multiple errors as JSON",
+			args: []string{
+				"-validateQuery",
+				"-errorFormat=json",
+				"-queryIn=./tests/invalid/query/multiple_errors/input.yaml"},
+			expectedExitCode: 1,
+			expectedOutFile:  "./tests/invalid/query/multiple_errors/expected_error.json",
+		},
+		{
+			name: "Generate mermaid invalid config as JSON",
+			args: []string{
+				"-generateMermaid",
+				"-errorFormat=json",
+				"-configIn=./tests/invalid/config/multiple_errors/input.yaml"},
+			expectedExitCode: 1,
+			expectedOutFile:  "./tests/invalid/config/multiple_errors/expected_error.json",
+		},
+		{
+			name: "Execute query invalid query as JSON",
+			args: []string{
+				"-executeQuery",
+				"-errorFormat=json",
+				"-configIn=./tests/simple/config.yaml",
+				"-queryIn=./tests/invalid/query/multiple_errors/input.yaml"},
+			expectedExitCode: 1,
+			expectedOutFile:  "./tests/invalid/query/multiple_errors/expected_error.json",
+		},
+		{
+			name: "Invalid error format",
+			args: []string{
+				"-validateConfig",
+				"-errorFormat=xml",
+				"-configIn=./tests/simple/config.yaml"},
+			expectedExitCode: 1,
+			expectedOutFile:  "",
+		},
 		// Example: Microservices Architecture
 		{
 			name: "Example microservices validate config",
@@ -180,6 +245,338 @@ func TestCLICommands(t *testing.T) {
 			expectedExitCode: 0,
 			expectedOutFile:  "./tests/example_multi_file/mermaid.mmd",
 		},
+		// Example: Nested Folder Configuration
+		{
+			name: "Example nested folders merge config",
+			args: []string{
+				"-mergeConfig",
+				"-in=./tests/example_nested_folders/configs/"},
+			expectedExitCode: 0,
+			expectedOutFile:  "./tests/example_nested_folders/config.yaml",
+		},
+		{
+			name: "Example nested folders generate mermaid from folder",
+			args: []string{
+				"-generateMermaid",
+				"-configIn=./tests/example_nested_folders/configs/",
+				"-mermaidIn=./tests/example_nested_folders/mermaid.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "./tests/example_nested_folders/mermaid.mmd",
+		},
+		{
+			name: "Example nested folders merge config with invalid pattern",
+			args: []string{
+				"-mergeConfig",
+				"-exclude=[drafts",
+				"-in=./tests/example_nested_folders/configs/"},
+			expectedExitCode: 1,
+			expectedOutFile:  "",
+		},
+		// Example: Imports
+		{
+			name: "Example imports validate config",
+			args: []string{
+				"-validateConfig",
+				"-configIn=./tests/example_imports/configs/product.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "",
+		},
+		{
+			name: "Example imports generate mermaid",
+			args: []string{
+				"-generateMermaid",
+				"-configIn=./tests/example_imports/configs/product.yaml",
+				"-mermaidIn=./tests/example_imports/mermaid.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "./tests/example_imports/mermaid.mmd",
+		},
+		// Example: Namespaces
+		{
+			name: "Example namespaces generate mermaid",
+			args: []string{
+				"-generateMermaid",
+				"-configIn=./tests/example_namespaces/configs/",
+				"-mermaidIn=./tests/example_namespaces/mermaid.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "./tests/example_namespaces/mermaid.mmd",
+		},
+		// Example: Overlays
+		{
+			name: "Example overlays validate config",
+			args: []string{
+				"-validateConfig",
+				"-configIn=./tests/example_overlays/configs/prod.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "",
+		},
+		{
+			name: "Example overlays generate mermaid",
+			args: []string{
+				"-generateMermaid",
+				"-configIn=./tests/example_overlays/configs/prod.yaml",
+				"-mermaidIn=./tests/example_overlays/mermaid.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "./tests/example_overlays/mermaid.mmd",
+		},
+		// Example: Type Schemas
+		{
+			name: "Example schema merge config",
+			args: []string{
+				"-mergeConfig",
+				"-in=./tests/example_schema/configs/"},
+			expectedExitCode: 0,
+			expectedOutFile:  "./tests/example_schema/config.yaml",
+		},
+		// Example: Lint
+		{
+			name: "Example lint validate lint",
+			args: []string{
+				"-validateLint",
+				"-lintIn=./tests/example_lint/lint.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "",
+		},
+		{
+			name: "Example lint reports violations",
+			args: []string{
+				"-lint",
+				"-configIn=./tests/example_lint/config.yaml",
+				"-lintIn=./tests/example_lint/lint.yaml"},
+			expectedExitCode: 1,
+			expectedOutFile:  "./tests/example_lint/lint.txt",
+		},
+		{
+			name: "Invalid lint validate lint",
+			args: []string{
+				"-validateLint",
+				"-lintIn=./tests/invalid/lint/multiple_errors/input.yaml"},
+			expectedExitCode: 1,
+			expectedOutFile:  "./tests/invalid/lint/multiple_errors/expected_error.txt",
+		},
+		// Example: Dependency Cycles
+		{
+			name: "Example cycles detect cycles",
+			args: []string{
+				"-detectCycles",
+				"-configIn=./tests/example_cycles/config.yaml"},
+			expectedExitCode: 1,
+			expectedOutFile:  "./tests/example_cycles/cycles.txt",
+		},
+		{
+			name: "Example cycles detect cycles for link types",
+			args: []string{
+				"-detectCycles",
+				"-configIn=./tests/example_cycles/config.yaml",
+				"-linkTypes=REST",
+				"-linkTypes=Publish"},
+			expectedExitCode: 0,
+			expectedOutFile:  "",
+		},
+		{
+			name: "Simple detect cycles",
+			args: []string{
+				"-detectCycles",
+				"-configIn=./tests/simple/config.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "",
+		},
+		// Example: Graph Analysis
+		{
+			name: "Example microservices analyze",
+			args: []string{
+				"-analyze",
+				"-configIn=./tests/example_microservices/config.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "./tests/example_microservices/analysis.yaml",
+		},
+		{
+			name: "Example microservices analyze as JSON",
+			args: []string{
+				"-analyze",
+				"-format=json",
+				"-configIn=./tests/example_microservices/config.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "",
+		},
+		{
+			name: "Analyze with invalid format",
+			args: []string{
+				"-analyze",
+				"-format=table",
+				"-configIn=./tests/example_microservices/config.yaml"},
+			expectedExitCode: 1,
+			expectedOutFile:  "",
+		},
+		// Example: Graphviz DOT
+		{
+			name: "Example cloud infrastructure validate dot",
+			args: []string{
+				"-validateDot",
+				"-dotIn=./tests/example_cloud_infrastructure/dot.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "",
+		},
+		{
+			name: "Example cloud infrastructure generate dot",
+			args: []string{
+				"-generateDot",
+				"-configIn=./tests/example_cloud_infrastructure/config.yaml",
+				"-dotIn=./tests/example_cloud_infrastructure/dot.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "./tests/example_cloud_infrastructure/dot.gv",
+		},
+		{
+			name: "Simple generate dot with default settings",
+			args: []string{
+				"-generateDot",
+				"-configIn=./tests/simple/config.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "./tests/simple/dot.gv",
+		},
+		{
+			name: "Invalid dot validate dot",
+			args: []string{
+				"-validateDot",
+				"-dotIn=./tests/invalid/dot/multiple_errors/input.yaml"},
+			expectedExitCode: 1,
+			expectedOutFile:  "./tests/invalid/dot/multiple_errors/expected_error.txt",
+		},
+		// Example: PlantUML
+		{
+			name: "Example cloud infrastructure validate plantuml",
+			args: []string{
+				"-validatePlantUML",
+				"-plantumlIn=./tests/example_cloud_infrastructure/plantuml.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "",
+		},
+		{
+			name: "Example cloud infrastructure generate plantuml",
+			args: []string{
+				"-generatePlantUML",
+				"-configIn=./tests/example_cloud_infrastructure/config.yaml",
+				"-plantumlIn=./tests/example_cloud_infrastructure/plantuml.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "./tests/example_cloud_infrastructure/plantuml.puml",
+		},
+		{
+			name: "Simple generate plantuml with default settings",
+			args: []string{
+				"-generatePlantUML",
+				"-configIn=./tests/simple/config.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "./tests/simple/plantuml.puml",
+		},
+		{
+			name: "Invalid plantuml validate plantuml",
+			args: []string{
+				"-validatePlantUML",
+				"-plantumlIn=./tests/invalid/plantuml/multiple_errors/input.yaml"},
+			expectedExitCode: 1,
+			expectedOutFile:  "./tests/invalid/plantuml/multiple_errors/expected_error.txt",
+		},
+		// Example: C4 Model
+		{
+			name: "Example c4 validate c4",
+			args: []string{
+				"-validateC4",
+				"-c4In=./tests/example_c4/c4.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "",
+		},
+		{
+			name: "Example c4 generate c4",
+			args: []string{
+				"-generateC4",
+				"-configIn=./tests/example_c4/config.yaml",
+				"-c4In=./tests/example_c4/c4.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "./tests/example_c4/c4.mmd",
+		},
+		{
+			name: "Example c4 generate c4 without settings",
+			args: []string{
+				"-generateC4",
+				"-configIn=./tests/example_c4/config.yaml"},
+			expectedExitCode: 1,
+			expectedOutFile:  "",
+		},
+		{
+			name: "Invalid c4 validate c4",
+			args: []string{
+				"-validateC4",
+				"-c4In=./tests/invalid/c4/multiple_errors/input.yaml"},
+			expectedExitCode: 1,
+			expectedOutFile:  "./tests/invalid/c4/multiple_errors/expected_error.txt",
+		},
+		// Example: Structurizr
+		{
+			name: "Example c4 validate structurizr",
+			args: []string{
+				"-validateStructurizr",
+				"-structurizrIn=./tests/example_c4/structurizr.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "",
+		},
+		{
+			name: "Example c4 validate structurizr with input flag",
+			args: []string{
+				"-validateStructurizr",
+				"-in=./tests/example_c4/structurizr.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "",
+		},
+		{
+			name: "Example c4 generate structurizr",
+			args: []string{
+				"-generateStructurizr",
+				"-configIn=./tests/example_c4/config.yaml",
+				"-structurizrIn=./tests/example_c4/structurizr.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "./tests/example_c4/structurizr.dsl",
+		},
+		{
+			name: "Invalid structurizr validate structurizr",
+			args: []string{
+				"-validateStructurizr",
+				"-structurizrIn=./tests/invalid/structurizr/multiple_errors/input.yaml"},
+			expectedExitCode: 1,
+			expectedOutFile:  "./tests/invalid/structurizr/multiple_errors/expected_error.txt",
+		},
+		// Example: D2
+		{
+			name: "Example cloud infrastructure validate d2",
+			args: []string{
+				"-validateD2",
+				"-d2In=./tests/example_cloud_infrastructure/d2.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "",
+		},
+		{
+			name: "Example cloud infrastructure generate d2",
+			args: []string{
+				"-generateD2",
+				"-configIn=./tests/example_cloud_infrastructure/config.yaml",
+				"-d2In=./tests/example_cloud_infrastructure/d2.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "./tests/example_cloud_infrastructure/d2.d2",
+		},
+		{
+			name: "Simple generate d2 with default settings",
+			args: []string{
+				"-generateD2",
+				"-configIn=./tests/simple/config.yaml"},
+			expectedExitCode: 0,
+			expectedOutFile:  "./tests/simple/d2.d2",
+		},
+		{
+			name: "Invalid d2 validate d2",
+			args: []string{
+				"-validateD2",
+				"-d2In=./tests/invalid/d2/multiple_errors/input.yaml"},
+			expectedExitCode: 1,
+			expectedOutFile:  "./tests/invalid/d2/multiple_errors/expected_error.txt",
+		},
 		// Example: Styled Mermaid Diagram
 		{
 			name: "Example styled diagram validate config",